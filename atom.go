@@ -0,0 +1,183 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// The namespace of the Atom 1.0 elements, see
+// http://tools.ietf.org/html/rfc4287
+const AtomNamespace = "http://www.w3.org/2005/Atom"
+
+// Common AtomLink.Rel values
+const (
+	// The URL of the feed itself
+	AtomRelSelf = "self"
+
+	// The URL of a WebSub hub for the feed
+	AtomRelHub = "hub"
+
+	// The URL of the next page of a paged feed
+	AtomRelNext = "next"
+
+	// The URL of the previous archive document of an archived feed, see
+	// http://tools.ietf.org/html/rfc5005
+	AtomRelPrevArchive = "prev-archive"
+
+	// An alternate version of the resource, the default
+	AtomRelAlternate = "alternate"
+)
+
+// A link to a related resource
+type AtomLink struct {
+	// Required. The URL of the resource
+	Href string `xml:"href,attr"`
+
+	// Optional. The relationship to the resource, one of the AtomRel
+	// constants or a URI. When empty the relationship is
+	// rssgo.AtomRelAlternate
+	Rel string `xml:"rel,attr,omitempty"`
+
+	// Optional. The resource's MIME type
+	Type string `xml:"type,attr,omitempty"`
+
+	// Optional. The language of the resource
+	Hreflang string `xml:"hreflang,attr,omitempty"`
+
+	// Optional. The title of the link
+	Title string `xml:"title,attr,omitempty"`
+
+	// Optional. The size of the resource in bytes, see AtomLink.LengthValue
+	Length string `xml:"length,attr,omitempty"`
+}
+
+// Returns the size of the resource in bytes and true, or false if the length
+// isn't set or isn't an integer.
+func (link *AtomLink) LengthValue() (int64, bool) {
+	return parseSize(link.Length)
+}
+
+// Returns the first of the channel's atom:link elements with the
+// relationship, or nil.
+func (rss *Rss) AtomLink(rel string) *AtomLink {
+	return findAtomLink(rss.AtomLinks, rel)
+}
+
+// Returns the first link with the relationship, or nil.
+func findAtomLink(links []AtomLink, rel string) *AtomLink {
+	for i := 0; i != len(links); i++ {
+		linkRel := links[i].Rel
+		if linkRel == "" {
+			linkRel = AtomRelAlternate
+		}
+		if linkRel == rel {
+			return &links[i]
+		}
+	}
+	return nil
+}
+
+// Verifies that the channel's atom:link elements, including the self link, have
+// absolute URLs.
+func (v *verifier) verifyAtomLinks(path string, links []AtomLink) {
+	for i := 0; i != len(links); i++ {
+		name := "atom:link href"
+		if links[i].Rel != "" {
+			name = fmt.Sprintf("atom:link %v href", links[i].Rel)
+		}
+		v.verifyURL(fmt.Sprintf("%v[%v].Href", path, i), name, links[i].Href)
+		if length, ok := links[i].LengthValue(); links[i].Length != "" && (!ok || length < 0) {
+			v.add(fmt.Sprintf("%v[%v].Length", path, i), CodeRange,
+				"The atom:link length must be an integer that isn't negative.")
+		}
+	}
+}
+
+// The Atom elements of a channel that have no RSS equivalent, usually from a
+// feed read by rssgo.ParseAtom
+type AtomChannel struct {
+	// Optional. The feed's permanent, universally unique identifier
+	Id string `xml:"http://www.w3.org/2005/Atom id,omitempty"`
+
+	// Optional. The URL of a small square image for the feed
+	Icon string `xml:"http://www.w3.org/2005/Atom icon,omitempty"`
+
+	// Optional. The people who contributed to the feed
+	Contributors []AtomPerson `xml:"http://www.w3.org/2005/Atom contributor"`
+}
+
+// The Atom elements of an item that have no RSS equivalent, usually from an
+// entry read by rssgo.ParseAtom
+type AtomItem struct {
+	// Optional. When the entry was last changed
+	Updated W3CTime `xml:"http://www.w3.org/2005/Atom updated"`
+
+	// Optional. The rights held in the entry
+	Rights string `xml:"http://www.w3.org/2005/Atom rights,omitempty"`
+
+	// Optional. The people who contributed to the entry
+	Contributors []AtomPerson `xml:"http://www.w3.org/2005/Atom contributor"`
+
+	// Optional. The entry's links other than its Link and Enclosure
+	Links []AtomLink `xml:"http://www.w3.org/2005/Atom link"`
+}
+
+// An Atom author or contributor
+type AtomPerson struct {
+	// Required. The person's name
+	Name string `xml:"http://www.w3.org/2005/Atom name"`
+
+	// Optional. The person's email address
+	Email string `xml:"http://www.w3.org/2005/Atom email,omitempty"`
+
+	// Optional. The URL of the person's home page
+	Uri string `xml:"http://www.w3.org/2005/Atom uri,omitempty"`
+}
+
+// Writes the elements without an enclosing element.
+func (a AtomChannel) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type plain AtomChannel
+	return marshalFlat(e, plain(a))
+}
+
+// Writes the elements without an enclosing element.
+func (a AtomItem) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type plain AtomItem
+	return marshalFlat(e, plain(a))
+}
+
+// Verifies the channel's Atom elements.
+func (v *verifier) verifyAtomChannel(path string, a *AtomChannel) {
+	if a.Icon != "" {
+		v.verifyURL(path+".Icon", "atom:icon", a.Icon)
+	}
+	v.verifyAtomPersons(path+".Contributors", a.Contributors)
+}
+
+// Verifies the item's Atom elements.
+func (v *verifier) verifyAtomItem(path string, a *AtomItem) {
+	if a.Updated.Text != "" {
+		if _, err := ParseW3CDate(strings.TrimSpace(a.Updated.Text)); err != nil {
+			v.add(path+".Updated", CodeDate, fmt.Sprintf("Unable to parse the atom:updated date (%v)", err))
+		}
+	}
+	v.verifyAtomPersons(path+".Contributors", a.Contributors)
+	v.verifyAtomLinks(path+".Links", a.Links)
+}
+
+// Verifies Atom authors or contributors.
+func (v *verifier) verifyAtomPersons(path string, persons []AtomPerson) {
+	for i := 0; i != len(persons); i++ {
+		if strings.TrimSpace(persons[i].Name) == "" {
+			v.add(fmt.Sprintf("%v[%v].Name", path, i), CodeRequired, "The atom person's name must be set.")
+		}
+		if persons[i].Uri != "" {
+			v.verifyURL(fmt.Sprintf("%v[%v].Uri", path, i), "atom person uri", persons[i].Uri)
+		}
+	}
+}
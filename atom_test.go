@@ -0,0 +1,99 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestAtomLinks(t *testing.T) {
+
+	doc := `<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom"><channel>
+<title>title</title><link>http://github.com/efarrer/rssgo/</link><description>A blog</description>
+<atom:link href="http://example.com/feed.xml" rel="self" type="application/rss+xml"/>
+<atom:link href="http://pubsubhubbub.appspot.com/" rel="hub"/>
+<atom:link href="http://example.com/feed.xml?page=2" rel="next"/>
+<atom:link href="http://example.com/archive/1.xml" rel="prev-archive"/>
+<atom:link href="http://example.com/" length="1024"/>
+</channel></rss>`
+
+	rss, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing the document\n", err)
+	}
+	if rss.Link != "http://github.com/efarrer/rssgo/" || len(rss.AtomLinks) != 5 || len(rss.Extensions) != 0 {
+		t.Fatalf("Unexpected channel %#v\n", rss)
+	}
+	for rel, href := range map[string]string{
+		AtomRelSelf:        "http://example.com/feed.xml",
+		AtomRelHub:         "http://pubsubhubbub.appspot.com/",
+		AtomRelNext:        "http://example.com/feed.xml?page=2",
+		AtomRelPrevArchive: "http://example.com/archive/1.xml",
+		AtomRelAlternate:   "http://example.com/",
+	} {
+		if link := rss.AtomLink(rel); link == nil || link.Href != href {
+			t.Fatalf("Unexpected %v link %#v\n", rel, link)
+		}
+	}
+	if rss.AtomLink("previous") != nil {
+		t.Fatalf("Unexpected previous link\n")
+	}
+	if rss.AtomLink(AtomRelSelf).Type != "application/rss+xml" {
+		t.Fatalf("Unexpected self link %#v\n", rss.AtomLink(AtomRelSelf))
+	}
+	if length, ok := rss.AtomLink(AtomRelAlternate).LengthValue(); !ok || length != 1024 {
+		t.Fatalf("Unexpected alternate link length %v %v\n", length, ok)
+	}
+	if err := Verify(rss); err != nil {
+		t.Fatalf("Unexpected verify error %v\n", err)
+	}
+
+	// A bad length doesn't fail Parse, Verify reports it
+	bad, err := Parse(strings.NewReader(strings.Replace(doc, `length="1024"`, `length="big"`, 1)))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing a bad length\n", err)
+	}
+	if _, ok := bad.AtomLink(AtomRelAlternate).LengthValue(); ok || bad.AtomLink(AtomRelAlternate).Length != "big" {
+		t.Fatalf("Unexpected alternate link %#v\n", bad.AtomLink(AtomRelAlternate))
+	}
+	errs := VerifyAll(bad)
+	if len(errs) != 1 || errs[0].Field != "AtomLinks[4].Length" || errs[0].Code != CodeRange {
+		t.Fatalf("Unexpected verify errors %v\n", errs)
+	}
+
+	// The links are written in the Atom namespace
+	rss = &Rss{Version: Version, Title: "title", Link: "http://github.com/efarrer/rssgo/",
+		Description: "A blog",
+		AtomLinks: []AtomLink{
+			{Href: "http://example.com/feed.xml", Rel: AtomRelSelf, Type: "application/rss+xml"},
+			{Href: "http://example.com/hub", Rel: AtomRelHub}}}
+	data, err := xml.Marshal(rss)
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) marshalling the document\n", err)
+	}
+	expected := `<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom"><channel><title>title</title>` +
+		`<link>http://github.com/efarrer/rssgo/</link><description>A blog</description>` +
+		`<atom:link href="http://example.com/feed.xml" rel="self" type="application/rss+xml"></atom:link>` +
+		`<atom:link href="http://example.com/hub" rel="hub"></atom:link></channel></rss>`
+	if string(data) != expected {
+		t.Fatalf("Unexpected marshalled document expected:\n%v\ngot:\n%v\n", expected, string(data))
+	}
+	reparsed, err := Parse(bytes.NewReader(data))
+	if err != nil || len(reparsed.AtomLinks) != 2 || reparsed.Link != rss.Link {
+		t.Fatalf("Unexpected reparsed document (%v) %#v\n", err, reparsed)
+	}
+
+	// The links must be absolute URLs
+	rss.AtomLinks[0].Href = "/feed.xml"
+	rss.AtomLinks[1].Href = ""
+	errs = VerifyAll(rss)
+	if len(errs) != 2 || errs[0].Field != "AtomLinks[0].Href" || errs[0].Code != CodeURLNotAbsolute ||
+		errs[1].Field != "AtomLinks[1].Href" || errs[1].Code != CodeRequired {
+		t.Fatalf("Unexpected verify errors %v\n", errs)
+	}
+}
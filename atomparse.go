@@ -0,0 +1,566 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// Parses an Atom 1.0 (RFC 4287) document into a Rss object. The same
+// character sets as rssgo.Parse are supported. The Atom elements are mapped
+// as follows:
+//
+//	feed title, subtitle, rights     Title, Description, Copyright
+//	feed link                        Link for the first alternate link,
+//	                                 otherwise AtomLinks
+//	feed updated                     LastBuildDate
+//	feed author                      ManagingEditor and DC.Creators
+//	feed category, generator, logo   Categories, Generator, Image
+//	feed id, icon, contributor       Atom
+//	entry id                         Guid
+//	entry title, summary, content    Title, Description, Content
+//	entry link                       Link for the first alternate link,
+//	                                 Enclosure for the first enclosure link,
+//	                                 otherwise Atom.Links
+//	entry published                  PubDate
+//	entry author                     Author and DC.Creators
+//	entry category, source           Categories, Source
+//	entry updated, rights,           Atom
+//	entry contributor
+//
+// Elements from other namespaces are parsed as they are in an RSS document,
+// so iTunes or Media RSS elements are typed and unknown elements are kept in
+// Extensions. Text is converted to HTML, the Atom type of titles, and the
+// label of categories aren't kept. An entry without an author has the feed's
+// authors.
+//
+// The report lists the values that weren't converted exactly: those kept in
+// the Atom extensions, those that were changed or dropped, and the required
+// RSS values, such as the channel's description, that were synthesized.
+func ParseAtom(r io.Reader) (*Rss, ConversionReport, error) {
+	d := newXMLDecoder(r)
+	start, err := readRoot(d)
+	if err != nil {
+		return nil, nil, errors.New(fmt.Sprintf("Unable to parse the Atom document (%v)", err))
+	}
+	if start.Name.Space != AtomNamespace || start.Name.Local != "feed" {
+		return nil, nil, errors.New(fmt.Sprintf("Unable to parse the Atom document (Expecting an Atom <feed> element but found <%v>)",
+			start.Name.Local))
+	}
+
+	c := &atomConverter{rss: &Rss{Version: Version}}
+	if err := c.readFeed(d, start); err != nil {
+		return nil, nil, errors.New(fmt.Sprintf("Unable to parse the Atom document (%v)", err))
+	}
+	c.finish()
+	return c.rss, c.report, nil
+}
+
+// An Atom text construct or content element
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Src   string `xml:"src,attr"`
+	Text  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+// Returns the text as HTML.
+func (t *atomText) html() string {
+	switch strings.ToLower(t.Type) {
+	case "html":
+		return strings.TrimSpace(t.Text)
+	case "xhtml":
+		return xhtmlContent(t.Inner)
+	}
+	return html.EscapeString(strings.TrimSpace(t.Text))
+}
+
+// Returns true if the text is plain text rather than markup.
+func (t *atomText) plain() bool {
+	return t.Type == "" || strings.ToLower(t.Type) == "text"
+}
+
+// Returns the markup inside the <div> of an xhtml text construct.
+func xhtmlContent(inner string) string {
+	inner = strings.TrimSpace(inner)
+	end := strings.Index(inner, ">")
+	if !strings.HasPrefix(inner, "<") || end == -1 || inner[end-1] == '/' {
+		return inner
+	}
+	if close := strings.LastIndex(inner, "</"); close > end {
+		return strings.TrimSpace(inner[end+1 : close])
+	}
+	return inner
+}
+
+// An atom:category element
+type atomCategory struct {
+	Term   string `xml:"term,attr"`
+	Scheme string `xml:"scheme,attr,omitempty"`
+	Label  string `xml:"label,attr,omitempty"`
+}
+
+// An atom:generator element
+type atomGenerator struct {
+	Uri     string `xml:"uri,attr,omitempty"`
+	Version string `xml:"version,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// An atom:source element
+type atomSource struct {
+	Title atomText   `xml:"http://www.w3.org/2005/Atom title"`
+	Links []AtomLink `xml:"http://www.w3.org/2005/Atom link"`
+}
+
+// The state of an Atom to RSS conversion
+type atomConverter struct {
+	rss    *Rss
+	report ConversionReport
+
+	// The feed's id, authors and logo, which are used once the feed has been
+	// read
+	id      string
+	authors []AtomPerson
+	logo    string
+
+	// The indexes of the items without authors
+	anonymous []int
+}
+
+// Reads the children of <feed> up to and including its end element.
+func (c *atomConverter) readFeed(d *xml.Decoder, feed xml.StartElement) error {
+	for _, attr := range feed.Attr {
+		switch {
+		case attr.Name.Space == xmlNamespace && attr.Name.Local == "lang":
+			c.rss.Language = strings.ToLower(attr.Value)
+		case attr.Name.Space == "xmlns":
+			c.rss.Attrs = append(c.rss.Attrs, attr)
+		case !isNamespaceDecl(attr):
+			c.report.add("Attrs", ConversionDropped, fmt.Sprintf("The feed's %v attribute was dropped", attr.Name.Local))
+		}
+	}
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space != AtomNamespace {
+				err = decodeChannelElement(d, c.rss, feed.Name, &t)
+			} else {
+				err = c.readFeedElement(d, &t)
+			}
+			if err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// Decodes a single Atom child element of <feed>.
+func (c *atomConverter) readFeedElement(d *xml.Decoder, start *xml.StartElement) error {
+	rss := c.rss
+	switch start.Name.Local {
+	case "entry":
+		return c.readEntry(d, start)
+
+	case "id":
+		if err := d.DecodeElement(&c.id, start); err != nil {
+			return err
+		}
+		c.id = strings.TrimSpace(c.id)
+		c.channelAtom().Id = c.id
+		c.report.add("Atom.Id", ConversionKept, "The feed's id was kept")
+
+	case "title", "subtitle", "rights":
+		var text atomText
+		if err := d.DecodeElement(&text, start); err != nil {
+			return err
+		}
+		switch start.Name.Local {
+		case "title":
+			rss.Title = c.plainText("Title", &text)
+		case "subtitle":
+			rss.Description = text.html()
+		case "rights":
+			rss.Copyright = c.plainText("Copyright", &text)
+		}
+
+	case "link":
+		var link AtomLink
+		if err := d.DecodeElement(&link, start); err != nil {
+			return err
+		}
+		if rss.Link == "" && (link.Rel == "" || link.Rel == AtomRelAlternate) {
+			rss.Link = link.Href
+		} else {
+			rss.AtomLinks = append(rss.AtomLinks, link)
+		}
+
+	case "updated":
+		var text string
+		if err := d.DecodeElement(&text, start); err != nil {
+			return err
+		}
+		rss.LastBuildDate = c.date("LastBuildDate", text)
+
+	case "author":
+		var person AtomPerson
+		if err := d.DecodeElement(&person, start); err != nil {
+			return err
+		}
+		c.authors = append(c.authors, person)
+
+	case "contributor":
+		var person AtomPerson
+		if err := d.DecodeElement(&person, start); err != nil {
+			return err
+		}
+		a := c.channelAtom()
+		a.Contributors = append(a.Contributors, person)
+		c.report.add(fmt.Sprintf("Atom.Contributors[%v]", len(a.Contributors)-1), ConversionKept,
+			"The feed's contributor was kept")
+
+	case "category":
+		category, err := c.category(d, start, fmt.Sprintf("Categories[%v]", len(rss.Categories)))
+		if err != nil {
+			return err
+		}
+		rss.Categories = append(rss.Categories, category)
+
+	case "generator":
+		var generator atomGenerator
+		if err := d.DecodeElement(&generator, start); err != nil {
+			return err
+		}
+		rss.Generator = strings.TrimSpace(strings.TrimSpace(generator.Text) + " " + generator.Version)
+		if generator.Uri != "" {
+			c.report.add("Generator", ConversionDropped, fmt.Sprintf("The generator's uri %v was dropped", generator.Uri))
+		}
+
+	case "icon":
+		a := c.channelAtom()
+		if err := d.DecodeElement(&a.Icon, start); err != nil {
+			return err
+		}
+		a.Icon = strings.TrimSpace(a.Icon)
+		c.report.add("Atom.Icon", ConversionKept, "The feed's icon was kept")
+
+	case "logo":
+		if err := d.DecodeElement(&c.logo, start); err != nil {
+			return err
+		}
+		c.logo = strings.TrimSpace(c.logo)
+
+	default:
+		var el Element
+		if err := d.DecodeElement(&el, start); err != nil {
+			return err
+		}
+		rss.Extensions = append(rss.Extensions, el)
+		c.report.add(fmt.Sprintf("Extensions[%v]", len(rss.Extensions)-1), ConversionKept,
+			fmt.Sprintf("The unknown atom:%v element was kept", start.Name.Local))
+	}
+	return nil
+}
+
+// Reads an <entry> element into a new item.
+func (c *atomConverter) readEntry(d *xml.Decoder, entry *xml.StartElement) error {
+	item := Item{}
+	path := fmt.Sprintf("Items[%v]", len(c.rss.Items))
+	var authors []AtomPerson
+
+	for done := false; !done; {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space != AtomNamespace {
+				err = decodeItemElement(d, &item, entry.Name, &t)
+			} else if t.Name.Local == "author" {
+				var person AtomPerson
+				err = d.DecodeElement(&person, &t)
+				authors = append(authors, person)
+			} else {
+				err = c.readEntryElement(d, &t, path, &item)
+			}
+			if err != nil {
+				return err
+			}
+		case xml.EndElement:
+			done = true
+		}
+	}
+
+	if item.PubDate.IsZero() && item.Atom != nil && !item.Atom.Updated.IsZero() {
+		item.PubDate = c.date(path+".PubDate", item.Atom.Updated.Text)
+		c.report.add(path+".PubDate", ConversionSynthesized, "The entry has no published date, its updated date was used")
+	}
+
+	if len(authors) == 0 {
+		c.anonymous = append(c.anonymous, len(c.rss.Items))
+	} else {
+		item.Author = c.persons(path+".Author", authors, &item.DC)
+	}
+
+	c.rss.Items = append(c.rss.Items, item)
+	return nil
+}
+
+// Decodes a single Atom child element of <entry>, other than <author>.
+func (c *atomConverter) readEntryElement(d *xml.Decoder, start *xml.StartElement, path string, item *Item) error {
+	switch start.Name.Local {
+	case "id":
+		var id string
+		if err := d.DecodeElement(&id, start); err != nil {
+			return err
+		}
+		item.Guid = &Guid{Guid: strings.TrimSpace(id)}
+
+	case "title":
+		var text atomText
+		if err := d.DecodeElement(&text, start); err != nil {
+			return err
+		}
+		item.Title = c.plainText(path+".Title", &text)
+
+	case "summary":
+		var text atomText
+		if err := d.DecodeElement(&text, start); err != nil {
+			return err
+		}
+		item.Description = text.html()
+
+	case "content":
+		var text atomText
+		if err := d.DecodeElement(&text, start); err != nil {
+			return err
+		}
+		switch {
+		case text.Src != "" && item.Link == "":
+			item.Link = text.Src
+			c.report.add(path+".Link", ConversionChanged, "The entry's out of line content is linked to")
+		case text.Src != "":
+			c.report.add(path+".Content", ConversionDropped,
+				fmt.Sprintf("The entry's out of line content %v was dropped", text.Src))
+		case text.plain() || containsString([]string{"html", "xhtml"}, strings.ToLower(text.Type)):
+			item.Content = text.html()
+		case strings.HasPrefix(strings.ToLower(text.Type), "text/"):
+			item.Content = html.EscapeString(strings.TrimSpace(text.Text))
+		default:
+			c.report.add(path+".Content", ConversionDropped,
+				fmt.Sprintf("The entry's %v content was dropped", text.Type))
+		}
+
+	case "link":
+		var link AtomLink
+		if err := d.DecodeElement(&link, start); err != nil {
+			return err
+		}
+		switch {
+		case item.Link == "" && (link.Rel == "" || link.Rel == AtomRelAlternate):
+			item.Link = link.Href
+		case item.Enclosure == nil && link.Rel == "enclosure":
+			length, ok := link.LengthValue()
+			if !ok && link.Length != "" {
+				c.report.add(path+".Enclosure.Length", ConversionDropped,
+					fmt.Sprintf("The enclosure link's length %q wasn't an integer and was dropped", link.Length))
+			}
+			item.Enclosure = &Enclosure{Url: link.Href, Length: length, Type: link.Type}
+		default:
+			a := itemAtom(item)
+			a.Links = append(a.Links, link)
+			c.report.add(fmt.Sprintf("%v.Atom.Links[%v]", path, len(a.Links)-1), ConversionKept,
+				"The entry's link was kept")
+		}
+
+	case "published":
+		var text string
+		if err := d.DecodeElement(&text, start); err != nil {
+			return err
+		}
+		item.PubDate = c.date(path+".PubDate", text)
+
+	case "updated":
+		var text string
+		if err := d.DecodeElement(&text, start); err != nil {
+			return err
+		}
+		itemAtom(item).Updated = W3CTimeFromString(strings.TrimSpace(text))
+		c.report.add(path+".Atom.Updated", ConversionKept, "The entry's updated date was kept")
+
+	case "rights":
+		var text atomText
+		if err := d.DecodeElement(&text, start); err != nil {
+			return err
+		}
+		itemAtom(item).Rights = c.plainText(path+".Atom.Rights", &text)
+		c.report.add(path+".Atom.Rights", ConversionKept, "The entry's rights were kept")
+
+	case "contributor":
+		var person AtomPerson
+		if err := d.DecodeElement(&person, start); err != nil {
+			return err
+		}
+		a := itemAtom(item)
+		a.Contributors = append(a.Contributors, person)
+		c.report.add(fmt.Sprintf("%v.Atom.Contributors[%v]", path, len(a.Contributors)-1), ConversionKept,
+			"The entry's contributor was kept")
+
+	case "category":
+		category, err := c.category(d, start, fmt.Sprintf("%v.Categories[%v]", path, len(item.Categories)))
+		if err != nil {
+			return err
+		}
+		item.Categories = append(item.Categories, category)
+
+	case "source":
+		var source atomSource
+		if err := d.DecodeElement(&source, start); err != nil {
+			return err
+		}
+		link := findAtomLink(source.Links, AtomRelSelf)
+		if link == nil {
+			link = findAtomLink(source.Links, AtomRelAlternate)
+		}
+		if link == nil {
+			c.report.add(path+".Source", ConversionDropped, "The entry's source has no link and was dropped")
+			break
+		}
+		item.Source = &Source{Source: strings.TrimSpace(source.Title.Text), Url: link.Href}
+
+	default:
+		var el Element
+		if err := d.DecodeElement(&el, start); err != nil {
+			return err
+		}
+		item.Extensions = append(item.Extensions, el)
+		c.report.add(fmt.Sprintf("%v.Extensions[%v]", path, len(item.Extensions)-1), ConversionKept,
+			fmt.Sprintf("The unknown atom:%v element was kept", start.Name.Local))
+	}
+	return nil
+}
+
+// Fills in the values that depend on the whole feed.
+func (c *atomConverter) finish() {
+	rss := c.rss
+	for _, i := range c.anonymous {
+		if len(c.authors) != 0 {
+			item := &rss.Items[i]
+			item.Author = c.persons(fmt.Sprintf("Items[%v].Author", i), c.authors, &item.DC)
+		}
+	}
+	if len(c.authors) != 0 {
+		rss.ManagingEditor = c.persons("ManagingEditor", c.authors, &rss.DC)
+	}
+
+	if rss.Link == "" {
+		if self := rss.AtomLink(AtomRelSelf); self != nil {
+			rss.Link = self.Href
+		} else if strings.HasPrefix(c.id, "http://") || strings.HasPrefix(c.id, "https://") {
+			rss.Link = c.id
+		}
+		if rss.Link != "" {
+			c.report.add("Link", ConversionSynthesized, "The feed has no alternate link, "+rss.Link+" was used")
+		}
+	}
+
+	if strings.TrimSpace(rss.Description) == "" && rss.Title != "" {
+		rss.Description = html.EscapeString(rss.Title)
+		c.report.add("Description", ConversionSynthesized, "The feed has no subtitle, its title was used")
+	}
+
+	if c.logo != "" {
+		rss.Image = &Image{Url: c.logo, Title: rss.Title, Link: rss.Link}
+	}
+}
+
+// Returns the text of a construct that's plain text in RSS. Markup is kept,
+// and reported, as RSS has nowhere to record the text's type.
+func (c *atomConverter) plainText(field string, text *atomText) string {
+	if text.plain() {
+		return strings.TrimSpace(text.Text)
+	}
+	c.report.add(field, ConversionChanged, fmt.Sprintf("The %v markup was kept as text", text.Type))
+	return text.html()
+}
+
+// Reads an atom:category element.
+func (c *atomConverter) category(d *xml.Decoder, start *xml.StartElement, field string) (Category, error) {
+	var category atomCategory
+	if err := d.DecodeElement(&category, start); err != nil {
+		return Category{}, err
+	}
+	if category.Label != "" && category.Label != category.Term {
+		c.report.add(field, ConversionDropped, fmt.Sprintf("The category's label %#v was dropped", category.Label))
+	}
+	return Category{Category: category.Term, Domain: category.Scheme}, nil
+}
+
+// Converts an Atom date, reporting dates that aren't RFC 3339 dates.
+func (c *atomConverter) date(field, text string) RssTime {
+	text = strings.TrimSpace(text)
+	if t, err := ParseW3CDate(text); err == nil {
+		return NewRssTime(t)
+	}
+	c.report.add(field, ConversionChanged, fmt.Sprintf("%#v isn't an RFC 3339 date", text))
+	return RssTimeFromString(text)
+}
+
+// Returns the RSS author of Atom persons: the email address and name of the
+// first person with an email address. The names are added to the Dublin Core
+// creators, the other email addresses and the uris are reported as dropped.
+func (c *atomConverter) persons(field string, persons []AtomPerson, dc **DublinCore) string {
+	author := ""
+	for _, person := range persons {
+		name := strings.TrimSpace(person.Name)
+		if name != "" {
+			if *dc == nil {
+				*dc = &DublinCore{}
+			}
+			(*dc).Creators = append((*dc).Creators, name)
+		}
+		switch {
+		case person.Email != "" && author == "" && name != "":
+			author = fmt.Sprintf("%v (%v)", person.Email, name)
+		case person.Email != "" && author == "":
+			author = person.Email
+		case person.Email != "":
+			c.report.add(field, ConversionDropped, fmt.Sprintf("The email address of %v was dropped", name))
+		}
+		if person.Uri != "" {
+			c.report.add(field, ConversionDropped, fmt.Sprintf("The uri of %v was dropped", name))
+		}
+	}
+	return author
+}
+
+// Returns the channel's Atom extension, creating it if needed.
+func (c *atomConverter) channelAtom() *AtomChannel {
+	if c.rss.Atom == nil {
+		c.rss.Atom = &AtomChannel{}
+	}
+	return c.rss.Atom
+}
+
+// Returns the item's Atom extension, creating it if needed.
+func itemAtom(item *Item) *AtomItem {
+	if item.Atom == nil {
+		item.Atom = &AtomItem{}
+	}
+	return item.Atom
+}
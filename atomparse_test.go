@@ -0,0 +1,176 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAtom(t *testing.T) {
+
+	doc := `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd" xml:lang="en-US">
+<title type="text">dive into mark</title>
+<subtitle type="html">A &lt;em&gt;lot&lt;/em&gt; of effort went into making this effortless</subtitle>
+<updated>2005-07-31T12:29:29Z</updated>
+<id>tag:example.org,2003:3</id>
+<link rel="alternate" type="text/html" hreflang="en" href="http://example.org/"/>
+<link rel="self" type="application/atom+xml" href="http://example.org/feed.atom"/>
+<rights>Copyright (c) 2003, Mark Pilgrim</rights>
+<generator uri="http://www.example.com/" version="1.0">Example Toolkit</generator>
+<author><name>Mark Pilgrim</name><email>f8dy@example.com</email><uri>http://example.org/</uri></author>
+<contributor><name>Sam Ruby</name></contributor>
+<category term="tech" scheme="http://example.org/categories" label="Technology"/>
+<icon>http://example.org/icon.png</icon>
+<logo>http://example.org/logo.png</logo>
+<itunes:explicit>false</itunes:explicit><itunes:image href="http://example.org/cover.jpg"/><itunes:category text="Technology"/>
+<entry>
+<title>Atom draft-07 snapshot</title>
+<link rel="alternate" type="text/html" href="http://example.org/2005/04/02/atom"/>
+<link rel="enclosure" type="audio/mpeg" length="1337" href="http://example.org/audio/ph34r_my_podcast.mp3"/>
+<link rel="related" href="http://example.org/related"/>
+<id>tag:example.org,2003:3.2397</id>
+<updated>2005-07-31T12:29:29Z</updated>
+<published>2003-12-13T08:29:29-04:00</published>
+<author><name>Joe Gregorio</name></author>
+<summary>Tags &amp; things</summary>
+<content type="xhtml" xml:lang="en"><div xmlns="http://www.w3.org/1999/xhtml"><p><i>[Update: The Atom draft is finished.]</i></p></div></content>
+<category term="atom"/>
+<itunes:duration>1:02</itunes:duration>
+</entry>
+<entry>
+<title type="html">&lt;b&gt;Second&lt;/b&gt;</title>
+<id>http://example.org/2</id>
+<updated>2005-08-01T10:00:00Z</updated>
+<content type="html">&lt;p&gt;Hello&lt;/p&gt;</content>
+<source><title>Other</title><link rel="self" href="http://other.example.org/feed.atom"/></source>
+</entry>
+</feed>`
+
+	rss, report, err := ParseAtom(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing the document\n", err)
+	}
+	if rss.Version != Version || rss.Title != "dive into mark" ||
+		rss.Description != "A <em>lot</em> of effort went into making this effortless" ||
+		rss.Link != "http://example.org/" || rss.Language != "en-us" ||
+		rss.Copyright != "Copyright (c) 2003, Mark Pilgrim" || rss.Generator != "Example Toolkit 1.0" ||
+		rss.ManagingEditor != "f8dy@example.com (Mark Pilgrim)" || rss.DC.Creators[0] != "Mark Pilgrim" ||
+		!rss.LastBuildDate.Time.Equal(time.Date(2005, time.July, 31, 12, 29, 29, 0, time.UTC)) {
+		t.Fatalf("Unexpected channel %#v\n", rss)
+	}
+	if len(rss.AtomLinks) != 1 || rss.AtomLink(AtomRelSelf).Href != "http://example.org/feed.atom" ||
+		len(rss.Categories) != 1 || rss.Categories[0].Category != "tech" ||
+		rss.Categories[0].Domain != "http://example.org/categories" ||
+		rss.Image.Url != "http://example.org/logo.png" || rss.Image.Link != "http://example.org/" {
+		t.Fatalf("Unexpected channel %#v\n", rss)
+	}
+	if rss.Atom.Id != "tag:example.org,2003:3" || rss.Atom.Icon != "http://example.org/icon.png" ||
+		rss.Atom.Contributors[0].Name != "Sam Ruby" {
+		t.Fatalf("Unexpected channel Atom %#v\n", rss.Atom)
+	}
+	if rss.ITunes == nil || rss.ITunes.Explicit != "false" || len(rss.Extensions) != 0 {
+		t.Fatalf("Unexpected channel extensions %#v %#v\n", rss.ITunes, rss.Extensions)
+	}
+
+	if len(rss.Items) != 2 {
+		t.Fatalf("Unexpected items %#v\n", rss.Items)
+	}
+	item := rss.Items[0]
+	if item.Title != "Atom draft-07 snapshot" || item.Link != "http://example.org/2005/04/02/atom" ||
+		item.Guid.Guid != "tag:example.org,2003:3.2397" || item.Guid.IsPermaLink ||
+		item.Description != "Tags &amp; things" ||
+		item.Content != `<p><i>[Update: The Atom draft is finished.]</i></p>` ||
+		item.Enclosure.Url != "http://example.org/audio/ph34r_my_podcast.mp3" || item.Enclosure.Length != 1337 ||
+		item.Author != "" || item.DC.Creators[0] != "Joe Gregorio" || item.Categories[0].Category != "atom" ||
+		item.ITunes.Duration != "1:02" ||
+		!item.PubDate.Time.Equal(time.Date(2003, time.December, 13, 12, 29, 29, 0, time.UTC)) {
+		t.Fatalf("Unexpected item %#v\n", item)
+	}
+	if item.Atom.Updated.Text != "2005-07-31T12:29:29Z" || len(item.Atom.Links) != 1 ||
+		item.Atom.Links[0].Rel != "related" {
+		t.Fatalf("Unexpected item Atom %#v\n", item.Atom)
+	}
+	item = rss.Items[1]
+	if item.Title != "<b>Second</b>" || item.Content != "<p>Hello</p>" || item.Author != "f8dy@example.com (Mark Pilgrim)" ||
+		item.Source.Source != "Other" || item.Source.Url != "http://other.example.org/feed.atom" ||
+		!item.PubDate.Time.Equal(time.Date(2005, time.August, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Unexpected item %#v\n", item)
+	}
+
+	expected := ConversionReport{
+		{Field: "Atom.Id", Conversion: ConversionKept},
+		{Field: "Generator", Conversion: ConversionDropped},
+		{Field: "Atom.Contributors[0]", Conversion: ConversionKept},
+		{Field: "Categories[0]", Conversion: ConversionDropped},
+		{Field: "Atom.Icon", Conversion: ConversionKept},
+		{Field: "Items[0].Atom.Links[0]", Conversion: ConversionKept},
+		{Field: "Items[0].Atom.Updated", Conversion: ConversionKept},
+		{Field: "Items[1].Title", Conversion: ConversionChanged},
+		{Field: "Items[1].Atom.Updated", Conversion: ConversionKept},
+		{Field: "Items[1].PubDate", Conversion: ConversionSynthesized},
+		{Field: "Items[1].Author", Conversion: ConversionDropped},
+		{Field: "ManagingEditor", Conversion: ConversionDropped},
+	}
+	if len(report) != len(expected) {
+		t.Fatalf("ParseAtom reported %v notes expected %v:\n%v\n", len(report), len(expected), report)
+	}
+	for i := 0; i != len(expected); i++ {
+		if report[i].Field != expected[i].Field || report[i].Conversion != expected[i].Conversion {
+			t.Fatalf("ParseAtom reported an unexpected note expected: %v/%v got: %v\n",
+				expected[i].Field, expected[i].Conversion, report[i])
+		}
+	}
+	if report.Lossless() || len(report.Filter(ConversionKept)) != 6 {
+		t.Fatalf("Unexpected report %v\n", report)
+	}
+
+	if err := Verify(rss); err != nil {
+		t.Fatalf("Unexpected verify error %v\n", err)
+	}
+	data, err := xml.Marshal(rss)
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) marshalling the document\n", err)
+	}
+	for _, text := range []string{
+		`<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd" xmlns:atom="http://www.w3.org/2005/Atom"`,
+		`<atom:id>tag:example.org,2003:3</atom:id><atom:icon>http://example.org/icon.png</atom:icon>`,
+		`<atom:updated>2005-07-31T12:29:29Z</atom:updated><atom:link href="http://example.org/related" rel="related"></atom:link>`,
+	} {
+		if !strings.Contains(string(data), text) {
+			t.Fatalf("Marshalled document is missing %v: %v\n", text, string(data))
+		}
+	}
+
+	// A minimal feed has its required RSS values synthesized
+	rss, report, err = ParseAtom(strings.NewReader(`<feed xmlns="http://www.w3.org/2005/Atom"><title>t</title>` +
+		`<id>http://example.org/</id><updated>2005-07-31T12:29:29Z</updated></feed>`))
+	if err != nil || rss.Link != "http://example.org/" || rss.Description != "t" ||
+		len(report.Filter(ConversionSynthesized)) != 2 || !report.Lossless() {
+		t.Fatalf("Unexpected minimal feed (%v) %#v %v\n", err, rss, report)
+	}
+
+	// An enclosure link with a bad length is kept without its length
+	rss, report, err = ParseAtom(strings.NewReader(`<feed xmlns="http://www.w3.org/2005/Atom"><title>t</title>` +
+		`<id>http://example.org/</id><updated>2005-07-31T12:29:29Z</updated><entry><title>e</title>` +
+		`<link rel="enclosure" href="http://example.org/a.mp3" length="big"/></entry></feed>`))
+	if err != nil || len(rss.Items) != 1 || rss.Items[0].Enclosure == nil || rss.Items[0].Enclosure.Length != 0 ||
+		len(report.Filter(ConversionDropped)) == 0 {
+		t.Fatalf("Unexpected enclosure (%v) %#v %v\n", err, rss, report)
+	}
+
+	for _, bad := range []string{
+		`<rss version="2.0"><channel></channel></rss>`,
+		`<feed><title>no namespace</title></feed>`,
+		`<feed xmlns="http://www.w3.org/2005/Atom"><title>unterminated`,
+	} {
+		if _, _, err := ParseAtom(strings.NewReader(bad)); err == nil {
+			t.Fatalf("Expected an error parsing %v\n", bad)
+		}
+	}
+}
@@ -0,0 +1,352 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Writes the Rss as an Atom 1.0 (RFC 4287) document. This is the reverse of
+// rssgo.ParseAtom, the values kept in the Atom extensions are written back.
+// The required Atom elements are derived as follows:
+//
+//	feed id         Atom.Id, the self atom:link or Link
+//	feed updated    LastBuildDate, PubDate or the newest entry's updated
+//	feed author     ManagingEditor and DC.Creators, only required when an
+//	                entry has no author
+//	entry id        Guid if it's an absolute IRI, otherwise Link
+//	entry updated   Atom.Updated, PubDate or DC.Date
+//	entry content   Content, or Description when there's no Link
+//
+// Dates are written in the RFC 3339 format. Categories are written as
+// atom:category and the Enclosure as a link with rel="enclosure". Elements
+// from other namespaces are written as they are in an RSS document, elements
+// of Extensions without a namespace are skipped as they would be read as
+// Atom elements. An error is returned if a required element can't be derived
+// or a date can't be parsed.
+func WriteAtom(w io.Writer, rss *Rss) error {
+	feed, err := newAtomFeed(rss)
+	if err != nil {
+		return errors.New(fmt.Sprintf("Unable to write the Atom document (%v)", err))
+	}
+
+	tokens, err := marshalTokens(feed)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	e := xml.NewEncoder(w)
+	ns := newNSWriter(e)
+	ns.declare(AtomNamespace, "")
+	ns.declareAttrs(rss.Attrs)
+	for _, tok := range tokens {
+		if t, ok := tok.(xml.StartElement); ok {
+			ns.useNames(t)
+		}
+	}
+
+	for _, tok := range tokens {
+		if err := ns.EncodeToken(tok); err != nil {
+			return err
+		}
+	}
+	return e.Flush()
+}
+
+// An Atom text construct to write
+type atomTextElement struct {
+	Type string `xml:"type,attr,omitempty"`
+	Text string `xml:",chardata"`
+}
+
+// The atom:source element to write
+type atomSourceElement struct {
+	Title string   `xml:"http://www.w3.org/2005/Atom title"`
+	Link  AtomLink `xml:"http://www.w3.org/2005/Atom link"`
+}
+
+// The <feed> element to write
+type atomFeed struct {
+	XMLName      xml.Name         `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang         string           `xml:"http://www.w3.org/XML/1998/namespace lang,attr,omitempty"`
+	Id           string           `xml:"http://www.w3.org/2005/Atom id"`
+	Title        atomTextElement  `xml:"http://www.w3.org/2005/Atom title"`
+	Subtitle     *atomTextElement `xml:"http://www.w3.org/2005/Atom subtitle"`
+	Updated      string           `xml:"http://www.w3.org/2005/Atom updated"`
+	Links        []AtomLink       `xml:"http://www.w3.org/2005/Atom link"`
+	Authors      []AtomPerson     `xml:"http://www.w3.org/2005/Atom author"`
+	Contributors []AtomPerson     `xml:"http://www.w3.org/2005/Atom contributor"`
+	Categories   []atomCategory   `xml:"http://www.w3.org/2005/Atom category"`
+	Generator    *atomGenerator   `xml:"http://www.w3.org/2005/Atom generator"`
+	Icon         string           `xml:"http://www.w3.org/2005/Atom icon,omitempty"`
+	Logo         string           `xml:"http://www.w3.org/2005/Atom logo,omitempty"`
+	Rights       string           `xml:"http://www.w3.org/2005/Atom rights,omitempty"`
+	ITunes       *ITunesChannel   `xml:"itunes"`
+	DC           *DublinCore      `xml:"dc"`
+	Podcast      *PodcastChannel  `xml:"podcast"`
+	Geo          *GeoLocation     `xml:"geo"`
+	Modules      Modules          `xml:"modules,omitempty"`
+	Extensions   []Element        `xml:",any"`
+	Entries      []atomEntry      `xml:"http://www.w3.org/2005/Atom entry"`
+}
+
+// An <entry> element to write
+type atomEntry struct {
+	Id            string             `xml:"http://www.w3.org/2005/Atom id"`
+	Title         atomTextElement    `xml:"http://www.w3.org/2005/Atom title"`
+	Updated       string             `xml:"http://www.w3.org/2005/Atom updated"`
+	Published     string             `xml:"http://www.w3.org/2005/Atom published,omitempty"`
+	Links         []AtomLink         `xml:"http://www.w3.org/2005/Atom link"`
+	Authors       []AtomPerson       `xml:"http://www.w3.org/2005/Atom author"`
+	Contributors  []AtomPerson       `xml:"http://www.w3.org/2005/Atom contributor"`
+	Categories    []atomCategory     `xml:"http://www.w3.org/2005/Atom category"`
+	Summary       *atomTextElement   `xml:"http://www.w3.org/2005/Atom summary"`
+	Content       *atomTextElement   `xml:"http://www.w3.org/2005/Atom content"`
+	Rights        string             `xml:"http://www.w3.org/2005/Atom rights,omitempty"`
+	Source        *atomSourceElement `xml:"http://www.w3.org/2005/Atom source"`
+	SlashComments string             `xml:"http://purl.org/rss/1.0/modules/slash/ comments,omitempty"`
+	CommentRss    string             `xml:"http://wellformedweb.org/CommentAPI/ commentRss,omitempty"`
+	InReplyTo     []InReplyTo        `xml:"http://purl.org/syndication/thread/1.0 in-reply-to"`
+	ITunes        *ITunesItem        `xml:"itunes"`
+	DC            *DublinCore        `xml:"dc"`
+	Media         *MediaItem         `xml:"media"`
+	Podcast       *PodcastItem       `xml:"podcast"`
+	Geo           *GeoLocation       `xml:"geo"`
+	Modules       Modules            `xml:"modules,omitempty"`
+	Extensions    []Element          `xml:",any"`
+}
+
+// Converts the Rss to an Atom feed.
+func newAtomFeed(rss *Rss) (*atomFeed, error) {
+	feed := &atomFeed{Lang: rss.Language, Rights: rss.Copyright, ITunes: rss.ITunes, DC: withoutCreators(rss.DC),
+		Podcast: rss.Podcast, Geo: rss.Geo, Modules: rss.Modules, Extensions: namespacedElements(rss.Extensions)}
+
+	if strings.TrimSpace(rss.Title) == "" {
+		return nil, errors.New("The atom:title can't be derived, Title isn't set")
+	}
+	feed.Title = atomTextElement{Text: rss.Title}
+	if rss.Description != "" {
+		feed.Subtitle = &atomTextElement{Type: "html", Text: rss.Description}
+	}
+
+	if rss.Link != "" {
+		feed.Links = append(feed.Links, AtomLink{Href: rss.Link, Rel: AtomRelAlternate, Type: "text/html"})
+	}
+	feed.Links = append(feed.Links, rss.AtomLinks...)
+
+	switch self := rss.AtomLink(AtomRelSelf); {
+	case rss.Atom != nil && rss.Atom.Id != "":
+		feed.Id = rss.Atom.Id
+	case self != nil && self.Href != "":
+		feed.Id = self.Href
+	case rss.Link != "":
+		feed.Id = rss.Link
+	default:
+		return nil, errors.New("The atom:id can't be derived, there's no Atom.Id, self atom:link or Link")
+	}
+
+	feed.Authors = atomPersons(rss.ManagingEditor, rss.DC)
+	for _, category := range rss.Categories {
+		feed.Categories = append(feed.Categories, atomCategory{Term: category.Category, Scheme: category.Domain})
+	}
+	if rss.Generator != "" {
+		feed.Generator = &atomGenerator{Text: rss.Generator}
+	}
+	if rss.Image != nil {
+		feed.Logo = rss.Image.Url
+	}
+	if rss.Atom != nil {
+		feed.Icon = rss.Atom.Icon
+		feed.Contributors = rss.Atom.Contributors
+	}
+
+	var newest time.Time
+	for i := 0; i != len(rss.Items); i++ {
+		entry, updated, err := newAtomEntry(fmt.Sprintf("Items[%v]", i), &rss.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		if len(entry.Authors) == 0 && len(feed.Authors) == 0 {
+			return nil, errors.New(fmt.Sprintf("The atom:author of Items[%v] can't be derived, "+
+				"neither the item or the channel has an author", i))
+		}
+		if updated.After(newest) {
+			newest = updated
+		}
+		feed.Entries = append(feed.Entries, *entry)
+	}
+
+	var err error
+	switch {
+	case !rss.LastBuildDate.IsZero():
+		feed.Updated, err = atomDate("LastBuildDate", rss.LastBuildDate.Text, rss.LastBuildDate.Time)
+	case !rss.PubDate.IsZero():
+		feed.Updated, err = atomDate("PubDate", rss.PubDate.Text, rss.PubDate.Time)
+	case !newest.IsZero():
+		feed.Updated = newest.Format(time.RFC3339)
+	default:
+		err = errors.New("The atom:updated can't be derived, there's no LastBuildDate, PubDate or item date")
+	}
+	return feed, err
+}
+
+// Converts an Item to an Atom entry. The entry's updated date is returned too.
+func newAtomEntry(path string, item *Item) (*atomEntry, time.Time, error) {
+	entry := &atomEntry{SlashComments: item.SlashComments, CommentRss: item.CommentRss, InReplyTo: item.InReplyTo,
+		ITunes: item.ITunes, DC: withoutCreators(item.DC), Media: item.Media, Podcast: item.Podcast, Geo: item.Geo,
+		Modules: item.Modules, Extensions: namespacedElements(item.Extensions)}
+
+	if strings.TrimSpace(item.Title) == "" {
+		return nil, time.Time{}, errors.New(fmt.Sprintf("The atom:title of %v can't be derived, Title isn't set", path))
+	}
+	entry.Title = atomTextElement{Text: item.Title}
+
+	if item.Guid != nil && isAbsoluteIRI(item.Guid.Guid) {
+		entry.Id = strings.TrimSpace(item.Guid.Guid)
+	} else if item.Link != "" {
+		entry.Id = item.Link
+	} else {
+		return nil, time.Time{}, errors.New(fmt.Sprintf("The atom:id of %v can't be derived, "+
+			"it has no link and its guid isn't an absolute IRI", path))
+	}
+
+	var updated time.Time
+	var err error
+	switch {
+	case item.Atom != nil && !item.Atom.Updated.IsZero():
+		entry.Updated, err = atomDate(path+".Atom.Updated", item.Atom.Updated.Text, item.Atom.Updated.Time)
+		updated = item.Atom.Updated.Time
+	case !item.PubDate.IsZero():
+		entry.Updated, err = atomDate(path+".PubDate", item.PubDate.Text, item.PubDate.Time)
+		updated = item.PubDate.Time
+	case item.DC != nil && !item.DC.Date.IsZero():
+		entry.Updated, err = atomDate(path+".DC.Date", item.DC.Date.Text, item.DC.Date.Time)
+		updated = item.DC.Date.Time
+	default:
+		err = errors.New(fmt.Sprintf("The atom:updated of %v can't be derived, "+
+			"there's no Atom.Updated, PubDate or DC.Date", path))
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if !item.PubDate.IsZero() {
+		if entry.Published, err = atomDate(path+".PubDate", item.PubDate.Text, item.PubDate.Time); err != nil {
+			return nil, time.Time{}, err
+		}
+	}
+
+	if item.Link != "" {
+		entry.Links = append(entry.Links, AtomLink{Href: item.Link, Rel: AtomRelAlternate})
+	}
+	if item.Enclosure != nil {
+		link := AtomLink{Href: item.Enclosure.Url, Rel: "enclosure", Type: item.Enclosure.Type}
+		if item.Enclosure.Length != 0 {
+			link.Length = strconv.FormatInt(item.Enclosure.Length, 10)
+		}
+		entry.Links = append(entry.Links, link)
+	}
+
+	switch {
+	case item.Content != "":
+		entry.Content = &atomTextElement{Type: "html", Text: item.Content}
+		if item.Description != "" {
+			entry.Summary = &atomTextElement{Type: "html", Text: item.Description}
+		}
+	case item.Link == "" && item.Description != "":
+		entry.Content = &atomTextElement{Type: "html", Text: item.Description}
+	case item.Link == "":
+		return nil, time.Time{}, errors.New(fmt.Sprintf("The atom:content of %v can't be derived, "+
+			"it has no content, description or link", path))
+	case item.Description != "":
+		entry.Summary = &atomTextElement{Type: "html", Text: item.Description}
+	}
+
+	entry.Authors = atomPersons(item.Author, item.DC)
+	for _, category := range item.Categories {
+		entry.Categories = append(entry.Categories, atomCategory{Term: category.Category, Scheme: category.Domain})
+	}
+	if item.Source != nil {
+		entry.Source = &atomSourceElement{Title: item.Source.Source,
+			Link: AtomLink{Href: item.Source.Url, Rel: AtomRelSelf}}
+	}
+	if item.Atom != nil {
+		entry.Links = append(entry.Links, item.Atom.Links...)
+		entry.Contributors = item.Atom.Contributors
+		entry.Rights = item.Atom.Rights
+	}
+	return entry, updated, nil
+}
+
+// Returns the Atom persons of an RSS email address, such as
+// "jane@example.com (Jane Doe)", and the Dublin Core creators.
+func atomPersons(email string, dc *DublinCore) []AtomPerson {
+	var persons []AtomPerson
+	if email = strings.TrimSpace(email); email != "" {
+		person := AtomPerson{Name: email}
+		if open := strings.Index(email, " ("); open != -1 && strings.HasSuffix(email, ")") {
+			person = AtomPerson{Name: email[open+2 : len(email)-1], Email: email[:open]}
+		} else if strings.Contains(email, "@") && !strings.ContainsAny(email, " \t") {
+			person.Email = email
+		}
+		persons = append(persons, person)
+	}
+
+	if dc != nil {
+		for _, creator := range dc.Creators {
+			if creator = strings.TrimSpace(creator); creator != "" && (len(persons) == 0 || persons[0].Name != creator) {
+				persons = append(persons, AtomPerson{Name: creator})
+			}
+		}
+	}
+	return persons
+}
+
+// Returns the Dublin Core elements without the creators, which atomPersons
+// writes as atom:author elements.
+func withoutCreators(dc *DublinCore) *DublinCore {
+	if dc == nil {
+		return nil
+	}
+	copied := *dc
+	copied.Creators = nil
+	return &copied
+}
+
+// Returns the date in the RFC 3339 format, or an error if it couldn't be
+// parsed.
+func atomDate(field, text string, date time.Time) (string, error) {
+	if date.IsZero() {
+		return "", errors.New(fmt.Sprintf("The %v date %#v can't be parsed", field, text))
+	}
+	return date.Format(time.RFC3339), nil
+}
+
+// Returns true if the value is an absolute IRI, such as a URL or a tag URI.
+func isAbsoluteIRI(value string) bool {
+	u, err := url.Parse(strings.TrimSpace(value))
+	return err == nil && u.Scheme != "" && !strings.ContainsAny(value, " \t\n")
+}
+
+// Returns the elements that have a namespace.
+func namespacedElements(elements []Element) []Element {
+	var namespaced []Element
+	for _, el := range elements {
+		if el.XMLName.Space != "" {
+			namespaced = append(namespaced, el)
+		}
+	}
+	return namespaced
+}
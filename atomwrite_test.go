@@ -0,0 +1,134 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteAtom(t *testing.T) {
+
+	then := time.Date(1974, time.July, 23, 9, 10, 11, 0, time.UTC)
+	rss := &Rss{Version: Version, Title: "title", Link: "http://github.com/efarrer/rssgo/",
+		Description: "A <b>blog</b>", Language: "en-us", Copyright: "Copyright 2012",
+		ManagingEditor: "jane@example.com (Jane Doe)", Generator: "rssgo",
+		LastBuildDate: NewRssTime(then), Categories: []Category{{Category: "go", Domain: "http://example.com/tags"}},
+		Image:     &Image{Url: "http://example.com/logo.png", Title: "title", Link: "http://github.com/efarrer/rssgo/"},
+		AtomLinks: []AtomLink{{Href: "http://example.com/feed.atom", Rel: AtomRelSelf}},
+		Extensions: []Element{
+			{XMLName: xml.Name{Space: "http://example.com/a", Local: "rating"}, Tokens: []xml.Token{xml.CharData("5")}},
+			{XMLName: xml.Name{Local: "unknown"}}},
+		Items: []Item{
+			{Title: "one", Link: "http://example.com/one", Description: "summary", Content: "<p>one</p>",
+				Guid: &Guid{Guid: "tag:example.com,2012:1"}, PubDate: NewRssTime(then),
+				Enclosure:  &Enclosure{Url: "http://example.com/one.mp3", Length: 42, Type: "audio/mpeg"},
+				Categories: []Category{{Category: "news"}}, DC: &DublinCore{Creators: []string{"John Smith"}},
+				ITunes: &ITunesItem{Duration: "1:02"}},
+			{Title: "two", Description: "just a description", Guid: &Guid{Guid: "2"},
+				Link: "http://example.com/two", Atom: &AtomItem{Updated: W3CTimeFromString("1974-07-24T00:00:00Z")},
+				Source: &Source{Source: "Other", Url: "http://other.example.com/rss"}},
+			{Title: "three", Description: "no link", Guid: &Guid{Guid: "http://example.com/three", IsPermaLink: true},
+				DC: &DublinCore{Date: NewW3CTime(then)}},
+		}}
+
+	var buf bytes.Buffer
+	if err := WriteAtom(&buf, rss); err != nil {
+		t.Fatalf("Unexpected error (%v) writing the Atom document\n", err)
+	}
+	doc := buf.String()
+	for _, text := range []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<feed xml:lang="en-us" xmlns="http://www.w3.org/2005/Atom" xmlns:ns1="http://example.com/a" ` +
+			`xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd" xmlns:dc="http://purl.org/dc/elements/1.1/">`,
+		`<id>http://example.com/feed.atom</id><title>title</title><subtitle type="html">A &lt;b&gt;blog&lt;/b&gt;</subtitle>`,
+		`<updated>1974-07-23T09:10:11Z</updated>`,
+		`<link href="http://github.com/efarrer/rssgo/" rel="alternate" type="text/html"></link>`,
+		`<link href="http://example.com/feed.atom" rel="self"></link>`,
+		`<author><name>Jane Doe</name><email>jane@example.com</email></author>`,
+		`<category term="go" scheme="http://example.com/tags"></category><generator>rssgo</generator>`,
+		`<logo>http://example.com/logo.png</logo><rights>Copyright 2012</rights>`,
+		`<rights>Copyright 2012</rights><ns1:rating>5</ns1:rating><entry>`,
+		`<entry><id>tag:example.com,2012:1</id><title>one</title><updated>1974-07-23T09:10:11Z</updated>` +
+			`<published>1974-07-23T09:10:11Z</published><link href="http://example.com/one" rel="alternate"></link>` +
+			`<link href="http://example.com/one.mp3" rel="enclosure" type="audio/mpeg" length="42"></link>` +
+			`<author><name>John Smith</name></author><category term="news"></category>` +
+			`<summary type="html">summary</summary><content type="html">&lt;p&gt;one&lt;/p&gt;</content>` +
+			`<itunes:duration>1:02</itunes:duration></entry>`,
+		`<entry><id>http://example.com/two</id><title>two</title><updated>1974-07-24T00:00:00Z</updated>` +
+			`<link href="http://example.com/two" rel="alternate"></link><summary type="html">just a description</summary>` +
+			`<source><title>Other</title><link href="http://other.example.com/rss" rel="self"></link></source></entry>`,
+		`<entry><id>http://example.com/three</id><title>three</title><updated>1974-07-23T09:10:11Z</updated>` +
+			`<content type="html">no link</content>`,
+	} {
+		if !strings.Contains(doc, text) {
+			t.Fatalf("Atom document is missing %v: %v\n", text, doc)
+		}
+	}
+	if strings.Contains(doc, "unknown") {
+		t.Fatalf("Atom document has an element without a namespace: %v\n", doc)
+	}
+
+	// The document can be read back
+	atom, _, err := ParseAtom(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing the Atom document\n", err)
+	}
+	if atom.Title != rss.Title || atom.Link != rss.Link || atom.Description != rss.Description ||
+		atom.ManagingEditor != rss.ManagingEditor || len(atom.Items) != 3 ||
+		atom.Items[0].Guid.Guid != "tag:example.com,2012:1" || atom.Items[0].Content != "<p>one</p>" ||
+		atom.Items[0].Enclosure.Length != 42 || atom.Items[0].ITunes.Duration != "1:02" ||
+		!atom.Items[0].PubDate.Time.Equal(then) || atom.Items[1].Source.Url != "http://other.example.com/rss" {
+		t.Fatalf("Unexpected Atom document %#v\n", atom)
+	}
+
+	// The creators are only written as authors so the document doesn't grow
+	// when it's read back and written again
+	if strings.Contains(doc, "dc:creator") {
+		t.Fatalf("Atom document has creators that are also authors: %v\n", doc)
+	}
+	var rewritten bytes.Buffer
+	if err := WriteAtom(&rewritten, atom); err != nil {
+		t.Fatalf("Unexpected error (%v) writing the parsed Atom document\n", err)
+	}
+	again, _, err := ParseAtom(bytes.NewReader(rewritten.Bytes()))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing the rewritten Atom document\n", err)
+	}
+	var twice bytes.Buffer
+	if err := WriteAtom(&twice, again); err != nil {
+		t.Fatalf("Unexpected error (%v) writing the reparsed Atom document\n", err)
+	}
+	if twice.String() != rewritten.String() || strings.Contains(twice.String(), "dc:creator") {
+		t.Fatalf("Atom document changed when it was rewritten\n%v\n%v\n", rewritten.String(), twice.String())
+	}
+
+	// Required elements that can't be derived
+	for _, test := range []struct {
+		change func(rss *Rss)
+		err    string
+	}{
+		{func(rss *Rss) { rss.Title = "" }, "atom:title"},
+		{func(rss *Rss) { rss.Link, rss.AtomLinks = "", nil }, "atom:id"},
+		{func(rss *Rss) { rss.LastBuildDate = RssTimeFromString("yesterday") }, "LastBuildDate"},
+		{func(rss *Rss) { rss.LastBuildDate, rss.Items = RssTime{}, nil }, "atom:updated"},
+		{func(rss *Rss) { rss.ManagingEditor = "" }, "atom:author of Items[1]"},
+		{func(rss *Rss) { rss.Items[1].Title = "" }, "atom:title of Items[1]"},
+		{func(rss *Rss) { rss.Items[2].Guid = &Guid{Guid: "3"} }, "atom:id of Items[2]"},
+		{func(rss *Rss) { rss.Items[2].DC = nil }, "atom:updated of Items[2]"},
+		{func(rss *Rss) { rss.Items[2].Description = "" }, "atom:content of Items[2]"},
+	} {
+		broken := *rss
+		broken.Items = append([]Item{}, rss.Items...)
+		test.change(&broken)
+		err := WriteAtom(&bytes.Buffer{}, &broken)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Fatalf("Expected an error about %v got %v\n", test.err, err)
+		}
+	}
+}
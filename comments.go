@@ -0,0 +1,66 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"fmt"
+)
+
+// The namespace of the Slash module, see
+// http://web.resource.org/rss/1.0/modules/slash/
+const SlashNamespace = "http://purl.org/rss/1.0/modules/slash/"
+
+// The namespace of the Well-Formed Web comment API, see
+// http://wellformedweb.org/news/wfw_namespace_elements/
+const WfwNamespace = "http://wellformedweb.org/CommentAPI/"
+
+// The namespace of the Atom threading extension, see
+// http://tools.ietf.org/html/rfc4685
+const ThrNamespace = "http://purl.org/syndication/thread/1.0"
+
+// A resource that an item is a response to
+type InReplyTo struct {
+	// Required. The unique identifier of the resource, usually its guid
+	Ref string `xml:"ref,attr"`
+
+	// Optional. The URL of the resource
+	Href string `xml:"href,attr,omitempty"`
+
+	// Optional. The MIME type of the resource
+	Type string `xml:"type,attr,omitempty"`
+
+	// Optional. The URL of the feed that contains the resource
+	Source string `xml:"source,attr,omitempty"`
+}
+
+// Returns the number of comments on the item and true, or false if the item
+// doesn't have a <slash:comments> element or it isn't an integer.
+func (item *Item) CommentCount() (int, bool) {
+	return parseCount(item.SlashComments)
+}
+
+// Verifies the item's comment elements.
+func (v *verifier) verifyComments(path string, item *Item) {
+	if count, ok := item.CommentCount(); item.SlashComments != "" && (!ok || count < 0) {
+		v.add(path+".SlashComments", CodeRange, "The item comment count must be an integer that isn't negative.")
+	}
+
+	if item.CommentRss != "" {
+		v.verifyURL(path+".CommentRss", "item comment feed", item.CommentRss)
+	}
+
+	for i := 0; i != len(item.InReplyTo); i++ {
+		field := fmt.Sprintf("%v.InReplyTo[%v]", path, i)
+		if item.InReplyTo[i].Ref == "" {
+			v.add(field+".Ref", CodeRequired, "The item in-reply-to ref must be set.")
+		}
+		if item.InReplyTo[i].Href != "" {
+			v.verifyURL(field+".Href", "item in-reply-to href", item.InReplyTo[i].Href)
+		}
+		if item.InReplyTo[i].Source != "" {
+			v.verifyURL(field+".Source", "item in-reply-to source", item.InReplyTo[i].Source)
+		}
+	}
+}
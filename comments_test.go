@@ -0,0 +1,94 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestComments(t *testing.T) {
+
+	doc := `<rss version="2.0" xmlns:slash="http://purl.org/rss/1.0/modules/slash/"
+xmlns:wfw="http://wellformedweb.org/CommentAPI/" xmlns:thr="http://purl.org/syndication/thread/1.0"><channel>
+<title>title</title><link>http://github.com/efarrer/rssgo/</link><description>A forum</description>
+<item><title>one</title><comments>http://example.com/one#comments</comments>
+<slash:comments>12</slash:comments>
+<wfw:commentRss>http://example.com/one/comments.xml</wfw:commentRss>
+<thr:in-reply-to ref="tag:example.com,2012:post-1" href="http://example.com/post-1" type="text/html"/>
+</item>
+<item><title>two</title><slash:comments>0</slash:comments></item>
+<item><title>three</title></item>
+<item><title>four</title><slash:comments>many</slash:comments></item>
+</channel></rss>`
+
+	rss, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing the document\n", err)
+	}
+	item := rss.Items[0]
+	if count, ok := item.CommentCount(); !ok || count != 12 {
+		t.Fatalf("Unexpected comment count %v %v\n", count, ok)
+	}
+	if item.CommentRss != "http://example.com/one/comments.xml" || len(item.InReplyTo) != 1 ||
+		item.InReplyTo[0].Ref != "tag:example.com,2012:post-1" || item.InReplyTo[0].Type != "text/html" ||
+		len(item.Extensions) != 0 {
+		t.Fatalf("Unexpected item %#v\n", item)
+	}
+	if count, ok := rss.Items[1].CommentCount(); !ok || count != 0 {
+		t.Fatalf("Unexpected comment count %v %v\n", count, ok)
+	}
+	if _, ok := rss.Items[2].CommentCount(); ok {
+		t.Fatalf("Unexpected comment count\n")
+	}
+	if _, ok := rss.Items[3].CommentCount(); ok || rss.Items[3].SlashComments != "many" {
+		t.Fatalf("Unexpected non-numeric comment count %#v\n", rss.Items[3])
+	}
+
+	data, err := xml.Marshal(rss)
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) marshalling the document\n", err)
+	}
+	for _, text := range []string{
+		`<slash:comments>12</slash:comments>`,
+		`<wfw:commentRss>http://example.com/one/comments.xml</wfw:commentRss>`,
+		`<thr:in-reply-to ref="tag:example.com,2012:post-1" href="http://example.com/post-1" type="text/html"></thr:in-reply-to>`,
+		`<item><title>two</title><slash:comments>0</slash:comments></item><item><title>three</title></item>`,
+	} {
+		if !strings.Contains(string(data), text) {
+			t.Fatalf("Marshalled document is missing %v: %v\n", text, string(data))
+		}
+	}
+	reparsed, err := Parse(bytes.NewReader(data))
+	if err != nil || reparsed.Items[0].SlashComments != "12" || reparsed.Items[0].CommentRss != item.CommentRss ||
+		len(reparsed.Items[0].InReplyTo) != 1 {
+		t.Fatalf("Unexpected reparsed document (%v) %#v\n", err, reparsed)
+	}
+
+	// Violations
+	rss.Items = []Item{{Title: "one",
+		SlashComments: "-1", CommentRss: "comments.xml",
+		InReplyTo: []InReplyTo{{Href: "post-1"}}}, {Title: "two", SlashComments: "many"}}
+
+	expected := ValidationErrors{
+		{Field: "Items[0].SlashComments", Code: CodeRange},
+		{Field: "Items[0].CommentRss", Code: CodeURLNotAbsolute},
+		{Field: "Items[0].InReplyTo[0].Ref", Code: CodeRequired},
+		{Field: "Items[0].InReplyTo[0].Href", Code: CodeURLNotAbsolute},
+		{Field: "Items[1].SlashComments", Code: CodeRange},
+	}
+	errs := VerifyAll(rss)
+	if len(errs) != len(expected) {
+		t.Fatalf("VerifyAll returned %v errors expected %v: %v\n", len(errs), len(expected), errs)
+	}
+	for i := 0; i != len(expected); i++ {
+		if errs[i].Field != expected[i].Field || errs[i].Code != expected[i].Code {
+			t.Fatalf("VerifyAll returned unexpected error expected: %v/%v got: %v/%v\n",
+				expected[i].Field, expected[i].Code, errs[i].Field, errs[i].Code)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"strings"
+)
+
+// The namespace of the RSS content module, see
+// http://web.resource.org/rss/1.0/modules/content/
+const ContentNamespace = "http://purl.org/rss/1.0/modules/content/"
+
+// Returns the item's full body. This is the Content unless it's empty or only
+// white space, otherwise the Description, which is often only a summary.
+func (item *Item) Body() string {
+	if strings.TrimSpace(item.Content) != "" {
+		return item.Content
+	}
+	return item.Description
+}
@@ -0,0 +1,104 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestContent(t *testing.T) {
+
+	doc := `<rss version="2.0" xmlns:c="http://purl.org/rss/1.0/modules/content/"><channel>
+<title>title</title><link>http://github.com/efarrer/rssgo/</link><description>A blog</description>
+<item><title>one</title><description>The teaser</description>
+<c:encoded><![CDATA[<p>The <b>full</b> post]]></c:encoded></item>
+<item><title>two</title><description>Only a description</description></item>
+</channel></rss>`
+
+	rss, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing the document\n", err)
+	}
+	if rss.Items[0].Content != "<p>The <b>full</b> post" || len(rss.Items[0].Extensions) != 0 {
+		t.Fatalf("Unexpected item %#v\n", rss.Items[0])
+	}
+	if rss.Items[0].Body() != "<p>The <b>full</b> post" {
+		t.Fatalf("Unexpected body %v\n", rss.Items[0].Body())
+	}
+	if rss.Items[1].Body() != "Only a description" {
+		t.Fatalf("Unexpected body %v\n", rss.Items[1].Body())
+	}
+
+	// The content is written as CDATA, with the feed's prefix
+	data, err := xml.Marshal(rss)
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) marshalling the document\n", err)
+	}
+	if !strings.Contains(string(data), `xmlns:c="http://purl.org/rss/1.0/modules/content/"`) ||
+		!strings.Contains(string(data), "<c:encoded><![CDATA[<p>The <b>full</b> post]]></c:encoded>") ||
+		strings.Count(string(data), "encoded") != 2 {
+		t.Fatalf("Unexpected marshalled document %v\n", string(data))
+	}
+
+	// Content that ends CDATA still round trips
+	rss = &Rss{Version: Version, Title: "title", Link: "http://github.com/efarrer/rssgo/",
+		Description: "A blog",
+		Items:       []Item{{Title: "one", Content: "<p>a]]>b</p>"}}}
+	data, err = xml.MarshalIndent(rss, "", "  ")
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) marshalling the document\n", err)
+	}
+	if !strings.Contains(string(data), `xmlns:content="http://purl.org/rss/1.0/modules/content/"`) ||
+		!strings.Contains(string(data), "<content:encoded><![CDATA[") {
+		t.Fatalf("Unexpected marshalled document %v\n", string(data))
+	}
+	reparsed, err := Parse(bytes.NewReader(data))
+	if err != nil || reparsed.Items[0].Content != "<p>a]]>b</p>" {
+		t.Fatalf("Unexpected reparsed document (%v) %#v\n", err, reparsed)
+	}
+
+	// The Encoder writes CDATA too
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+	if err := e.WriteHeader(rss); err != nil {
+		t.Fatalf("Unexpected error (%v) writing the header\n", err)
+	}
+	if err := e.WriteItem(rss.Items[0]); err != nil {
+		t.Fatalf("Unexpected error (%v) writing the item\n", err)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatalf("Unexpected error (%v) closing the encoder\n", err)
+	}
+	if !strings.Contains(buf.String(),
+		`<content:encoded xmlns:content="http://purl.org/rss/1.0/modules/content/"><![CDATA[`) {
+		t.Fatalf("Unexpected encoded document %v\n", buf.String())
+	}
+
+	// Content without a description is a best practice violation
+	errs := VerifyWithOptions(rss, VerifyOptions{Profile: ProfileBestPractice})
+	found := false
+	for _, err := range errs {
+		if err.Field == "Items[0].Description" && err.Code == CodeMissingDescription &&
+			err.Severity == SeverityWarning {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Missing description wasn't reported %v\n", errs)
+	}
+	if err := Verify(rss); err != nil {
+		t.Fatalf("Content without a description should pass Verify %v\n", err)
+	}
+
+	// Content that's only white space is an error
+	rss.Items[0].Content = " \n\t"
+	errs = VerifyAll(rss)
+	if len(errs) != 1 || errs[0].Field != "Items[0].Content" || errs[0].Code != CodeRequired {
+		t.Fatalf("Unexpected verify errors for white space content %v\n", errs)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// How a value was changed when a feed was converted to or from a Rss
+type Conversion int
+
+const (
+	// The value has no equivalent and was discarded
+	ConversionDropped Conversion = iota
+
+	// The value has no equivalent field and was kept in an extension, such
+	// as Rss.Atom
+	ConversionKept
+
+	// The value was changed to fit its new field
+	ConversionChanged
+
+	// A required value was missing and was made up from other values
+	ConversionSynthesized
+)
+
+func (c Conversion) String() string {
+	switch c {
+	case ConversionDropped:
+		return "dropped"
+	case ConversionKept:
+		return "kept"
+	case ConversionChanged:
+		return "changed"
+	case ConversionSynthesized:
+		return "synthesized"
+	}
+	return fmt.Sprintf("Conversion(%d)", int(c))
+}
+
+// A value that wasn't converted exactly
+type ConversionNote struct {
+	// The path of the field in the converted feed, for example
+	// "Items[3].Description"
+	Field string
+
+	// What happened to the value
+	Conversion Conversion
+
+	// A description of the change
+	Message string
+}
+
+func (n ConversionNote) String() string {
+	return fmt.Sprintf("%v (%v): %v", n.Field, n.Conversion, n.Message)
+}
+
+// The values that weren't converted exactly, in document order
+type ConversionReport []ConversionNote
+
+func (r ConversionReport) String() string {
+	notes := make([]string, len(r))
+	for i, note := range r {
+		notes[i] = note.String()
+	}
+	return strings.Join(notes, "\n")
+}
+
+// Returns the notes with the conversion.
+func (r ConversionReport) Filter(conversion Conversion) ConversionReport {
+	var notes ConversionReport
+	for _, note := range r {
+		if note.Conversion == conversion {
+			notes = append(notes, note)
+		}
+	}
+	return notes
+}
+
+// Returns true if nothing was dropped or changed.
+func (r ConversionReport) Lossless() bool {
+	for _, note := range r {
+		if note.Conversion == ConversionDropped || note.Conversion == ConversionChanged {
+			return false
+		}
+	}
+	return true
+}
+
+// Records a note.
+func (r *ConversionReport) add(field string, conversion Conversion, message string) {
+	*r = append(*r, ConversionNote{field, conversion, message})
+}
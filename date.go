@@ -0,0 +1,249 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// How strictly rssgo.ParseDate parses dates
+type DateMode int
+
+const (
+	// Only RFC822 dates with 2 or 4 character years are accepted, as with
+	// rssgo.ParseRssDate
+	DateStrict DateMode = iota
+
+	// The dates commonly found in real-world feeds are also accepted. This
+	// includes RFC3339 dates, single digit days, missing or full weekdays, full
+	// month names, military and North American time zone names and trailing
+	// junk. Dates without a time zone are treated as UTC.
+	DateLenient
+)
+
+// Parses a date/time string into a time.Time type. The layout (as used by
+// time.Parse) that matched the date is also returned. Named time zones are
+// resolved to their offsets. In lenient mode the layout matches the date after
+// known time zone names have been replaced with numeric offsets and trailing
+// junk has been removed, and unknown names such as "CEST" are an error rather
+// than being treated as UTC.
+func ParseDate(date string, mode DateMode) (time.Time, string, error) {
+	if mode == DateLenient {
+		return parseLenientDate(date)
+	}
+
+	t, fields, err := scanRssDate(date)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	return t, fields.layout(), nil
+}
+
+// Controls how rssgo.ComposeDate writes a date. The zero value is the format
+// recommended by the RSS Advisory Board, see rssgo.DefaultDateFormat.
+type DateFormat struct {
+	// Leave out the day of the week, for example "Mon, "
+	NoWeekday bool
+
+	// Leave out the seconds
+	NoSeconds bool
+
+	// Write the time zone as its abbreviation, for example "PDT", instead of
+	// a numeric offset. Zones that rssgo.ParseRssDate can't resolve, such as
+	// "CEST", are still written as numeric offsets so that the date keeps its
+	// offset.
+	ZoneName bool
+
+	// Convert the date to UTC and write the time zone as +0000 (or GMT with
+	// ZoneName)
+	UTC bool
+}
+
+// Returns the format recommended by the RSS Advisory Board, RFC1123 with a
+// numeric offset. For example "Mon, 02 Jan 2006 15:04:05 -0700"
+func DefaultDateFormat() DateFormat {
+	return DateFormat{}
+}
+
+// Returns RFC1123 in GMT. For example "Mon, 02 Jan 2006 15:04:05 GMT"
+func GMTDateFormat() DateFormat {
+	return DateFormat{ZoneName: true, UTC: true}
+}
+
+// Composes a date/time string in the RSS 2.0 format (RFC822 with 4 character
+// year) as described by the format. Every format can be parsed with
+// rssgo.ParseRssDate, which returns the same instant.
+func ComposeDate(date time.Time, format DateFormat) string {
+	layout := ""
+	if !format.NoWeekday {
+		layout += dayPrefix
+	}
+	layout += dayMonth + fourYear
+	if format.NoSeconds {
+		layout += excludeSeconds
+	} else {
+		layout += includeSeconds
+	}
+
+	if format.UTC {
+		date = date.UTC()
+		if format.ZoneName {
+			return date.Format(layout + "GMT")
+		}
+	}
+	if format.ZoneName && knownZone(date) {
+		return date.Format(layout + zone)
+	}
+	return date.Format(layout + localDifferential)
+}
+
+// Returns true if rssgo.ParseRssDate resolves the date's zone abbreviation to
+// the date's offset.
+func knownZone(date time.Time) bool {
+	name, offset := date.Zone()
+	known, ok := zoneOffsets[name]
+	return ok && known*60 == offset
+}
+
+// Tries each of the lenient layouts, removing the last field of the date
+// until one matches. A date with a time zone name that isn't in zoneOffsets is
+// an error.
+func parseLenientDate(date string) (time.Time, string, error) {
+	fields := strings.Fields(date)
+	for len(fields) != 0 {
+		value := strings.Join(fields, " ")
+
+		// Replace a trailing named time zone with its offset
+		var loc *time.Location
+		last := fields[len(fields)-1]
+		if zoneLoc, ok := zoneLocations[strings.ToUpper(last)]; ok {
+			loc = zoneLoc
+			_, offset := time.Date(2000, 1, 1, 0, 0, 0, 0, loc).Zone()
+			value = value[:len(value)-len(last)] + formatOffset(offset)
+		}
+
+		for _, layout := range lenientLayouts {
+			t, err := time.Parse(layout, value)
+			if err != nil {
+				continue
+			}
+			// time.Parse gives zone names it doesn't know a zero offset,
+			// which would silently move the date
+			if name, _ := t.Zone(); strings.HasSuffix(layout, "MST") && zoneLocations[name] == nil {
+				return time.Time{}, "", errors.New(fmt.Sprintf("Unknown time zone %q in %q", name, date))
+			}
+			if loc != nil {
+				return t.In(loc), layout, nil
+			}
+			return resolveZone(t), layout, nil
+		}
+
+		fields = fields[:len(fields)-1]
+	}
+
+	return time.Time{}, "", errors.New(fmt.Sprintf("Unable to parse %q as a date", date))
+}
+
+// Moves a time parsed with a named time zone into the zone's location.
+func resolveZone(t time.Time) time.Time {
+	name, _ := t.Zone()
+	if loc, ok := zoneLocations[name]; ok && name != "UTC" {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(),
+			t.Second(), t.Nanosecond(), loc)
+	}
+	return t
+}
+
+// Formats a zone offset, in seconds, as +hhmm or -hhmm.
+func formatOffset(offset int) string {
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, offset/3600, offset%3600/60)
+}
+
+// The offsets, in minutes, of the time zone names found in feeds. This
+// includes the RFC822 names, the military zones and the North American zones.
+var zoneOffsets = map[string]int{
+	"UT":   0,
+	"UTC":  0,
+	"GMT":  0,
+	"Z":    0,
+	"EST":  -5 * 60,
+	"EDT":  -4 * 60,
+	"CST":  -6 * 60,
+	"CDT":  -5 * 60,
+	"MST":  -7 * 60,
+	"MDT":  -6 * 60,
+	"PST":  -8 * 60,
+	"PDT":  -7 * 60,
+	"AST":  -4 * 60,
+	"ADT":  -3 * 60,
+	"NST":  -(3*60 + 30),
+	"NDT":  -(2*60 + 30),
+	"AKST": -9 * 60,
+	"AKDT": -8 * 60,
+	"HST":  -10 * 60,
+	"HAST": -10 * 60,
+	"HADT": -9 * 60,
+}
+
+// The locations of the zones in zoneOffsets
+var zoneLocations map[string]*time.Location
+
+// The layouts tried by the lenient parser, most common first
+var lenientLayouts []string
+
+func init() {
+	// Military zones A-I and K-M are +1 to +12, N-Y are -1 to -12
+	for i, zone := range "ABCDEFGHIKLM" {
+		zoneOffsets[string(zone)] = (i + 1) * 60
+	}
+	for i, zone := range "NOPQRSTUVWXY" {
+		zoneOffsets[string(zone)] = -(i + 1) * 60
+	}
+
+	zoneLocations = map[string]*time.Location{}
+	for name, offset := range zoneOffsets {
+		zoneLocations[name] = time.FixedZone(name, offset*60)
+	}
+	zoneLocations["UTC"] = time.UTC
+
+	lenientLayouts = []string{
+		time.RFC1123Z,
+		time.RFC1123,
+		time.RFC3339,
+		"2006-01-02T15:04:05Z0700",
+		"2006-01-02T15:04Z07:00",
+		"2006-01-02 15:04:05Z07:00",
+		"2006-01-02 15:04:05 -0700",
+		"2006-01-02 15:04:05 MST",
+	}
+	for _, zone := range []string{"-0700", "MST", "-07:00"} {
+		for _, weekday := range []string{"Mon, ", "Mon ", "Monday, ", "Monday ", ""} {
+			for _, month := range []string{"Jan", "January"} {
+				for _, year := range []string{"2006", "06"} {
+					for _, clock := range []string{"15:04:05", "15:04"} {
+						lenientLayouts = append(lenientLayouts,
+							weekday+"2 "+month+" "+year+" "+clock+" "+zone)
+					}
+				}
+			}
+		}
+	}
+	lenientLayouts = append(lenientLayouts,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006-01-02",
+		"Mon, 2 Jan 2006 15:04:05",
+		"2 Jan 2006 15:04:05",
+		"Mon, 2 Jan 2006",
+		"2 Jan 2006")
+}
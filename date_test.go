@@ -0,0 +1,139 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+
+	testString := func(str string, mode DateMode, expected time.Time, zone string) {
+		actual, layout, err := ParseDate(str, mode)
+		if err != nil {
+			t.Fatalf("Unexpected error (%v) when parsing %v\n", err, str)
+		}
+		if !actual.Equal(expected) {
+			t.Fatalf("Unexpected time.Time when parsing %v. Expected: %v got: %v\n",
+				str, expected, actual)
+		}
+		// Numeric offsets may be reported in the local zone
+		if name, _ := actual.Zone(); zone != "" && name != zone {
+			t.Fatalf("Unexpected zone when parsing %v. Expected: %v got: %v\n",
+				str, zone, name)
+		}
+		if layout == "" {
+			t.Fatalf("No layout returned when parsing %v\n", str)
+		}
+	}
+	testFailure := func(str string, mode DateMode) {
+		if _, _, err := ParseDate(str, mode); err == nil {
+			t.Fatalf("Parsing %v in mode %v should fail\n", str, mode)
+		}
+	}
+
+	utc := time.Date(1974, time.July, 3, 9, 10, 30, 0, time.UTC)
+	edt := time.Date(1974, time.July, 3, 13, 10, 30, 0, time.UTC)
+
+	// Both modes
+	for _, mode := range []DateMode{DateStrict, DateLenient} {
+		testString("Wed, 03 Jul 1974 09:10:30 UTC", mode, utc, "UTC")
+		testString("Wed, 03 Jul 1974 09:10:30 GMT", mode, utc, "GMT")
+		testString("Wed, 03 Jul 1974 09:10:30 +0000", mode, utc, "")
+		testString("03 Jul 74 09:10:30 EDT", mode, edt, "EDT")
+		testString("Wed, 03 Jul 1974 09:10:30 -0400", mode, edt, "")
+		testString("Wed, 03 Jul 1974 06:40:30 NDT", mode, utc, "NDT")
+		testString("Wed, 3 Jul 1974 09:10:30 GMT", mode, utc, "GMT")
+		testString("Wed, 03 Jul 1974 09:10:30 Z", mode, utc, "Z")
+		testString("Wed, 03 Jul 1974 10:10:30 A", mode, utc, "A")
+		testString("Wed, 03 Jul 1974 08:10:30 N", mode, utc, "N")
+	}
+
+	// Lenient only
+	lenient := []struct {
+		str      string
+		expected time.Time
+		zone     string
+	}{
+		{"1974-07-03T09:10:30Z", utc, "UTC"},
+		{"1974-07-03T09:10:30-04:00", edt, ""},
+		{"1974-07-03T09:10:30.000Z", utc, "UTC"},
+		{"1974-07-03 09:10:30", utc, "UTC"},
+		{"Wed 03 Jul 1974 09:10:30 GMT", utc, "GMT"},
+		{"Wednesday, 03 July 1974 09:10:30 GMT", utc, "GMT"},
+		{"3 July 1974 09:10:30 +0000", utc, ""},
+		{"Wed, 03 Jul 1974 09:10:30 edt", edt, "EDT"},
+		{"Wed, 03 Jul 1974 09:10:30 GMT (Coordinated Universal Time)", utc, "GMT"},
+		{"  Wed,  03 Jul 1974 09:10:30 +0000  junk", utc, ""},
+		{"Wed, 03 Jul 1974 09:10:30 -04:00", edt, ""},
+	}
+	for _, test := range lenient {
+		testString(test.str, DateLenient, test.expected, test.zone)
+		testFailure(test.str, DateStrict)
+	}
+
+	// Neither mode
+	for _, mode := range []DateMode{DateStrict, DateLenient} {
+		testFailure("", mode)
+		testFailure("Some time tomorrow", mode)
+		testFailure("Wed, 33 Jul 1974 09:10:30 GMT", mode)
+	}
+
+	// Unknown zone names aren't treated as UTC
+	testFailure("Wed, 03 Jul 1974 11:10:30 CEST", DateLenient)
+	testFailure("Wed, 03 Jul 1974 19:10:30 AEST", DateLenient)
+	testFailure("1974-07-03 11:10:30 CEST", DateLenient)
+
+	// The layout is reported
+	if _, layout, _ := ParseDate("1974-07-03T09:10:30Z", DateLenient); layout != time.RFC3339 {
+		t.Fatalf("Unexpected layout expected: %v got: %v\n", time.RFC3339, layout)
+	}
+	if _, layout, _ := ParseDate("03 Jul 74 09:10 UTC", DateStrict); layout != "02 Jan 06 15:04 MST" {
+		t.Fatalf("Unexpected layout expected: %v got: %v\n", "02 Jan 06 15:04 MST", layout)
+	}
+}
+
+func TestComposeDate(t *testing.T) {
+
+	pdt := time.FixedZone("PDT", -7*60*60)
+	then := time.Date(1974, time.July, 23, 9, 10, 11, 12, pdt)
+
+	testFormat := func(format DateFormat, expected string) {
+		actual := ComposeDate(then, format)
+		if expected != actual {
+			t.Fatalf("ComposeDate returned incorrect date/time string expected: %v got: %v\n",
+				expected, actual)
+		}
+
+		parsed, err := ParseRssDate(actual)
+		if err != nil {
+			t.Fatalf("Unable to parse composed date %v (%v)\n", actual, err)
+		}
+		truncated := then.Truncate(time.Second)
+		if format.NoSeconds {
+			truncated = then.Truncate(time.Minute)
+		}
+		if !parsed.Equal(truncated) {
+			t.Fatalf("Composed date %v didn't round-trip expected: %v got: %v\n",
+				actual, truncated, parsed)
+		}
+	}
+
+	testFormat(DefaultDateFormat(), "Tue, 23 Jul 1974 09:10:11 -0700")
+	testFormat(GMTDateFormat(), "Tue, 23 Jul 1974 16:10:11 GMT")
+	testFormat(DateFormat{}, ComposeDate(then, DefaultDateFormat()))
+	testFormat(DateFormat{NoWeekday: true, NoSeconds: true, ZoneName: true}, ComposeRssDate(then))
+	testFormat(DateFormat{NoWeekday: true, NoSeconds: true, ZoneName: true}, "23 Jul 1974 09:10 PDT")
+	testFormat(DateFormat{NoSeconds: true, ZoneName: true}, "Tue, 23 Jul 1974 09:10 PDT")
+	testFormat(DateFormat{NoWeekday: true, ZoneName: true}, "23 Jul 1974 09:10:11 PDT")
+	testFormat(DateFormat{NoWeekday: true, NoSeconds: true}, "23 Jul 1974 09:10 -0700")
+	testFormat(DateFormat{NoWeekday: true, NoSeconds: true, ZoneName: true, UTC: true}, "23 Jul 1974 16:10 GMT")
+	testFormat(DateFormat{NoWeekday: true, NoSeconds: true, UTC: true}, "23 Jul 1974 16:10 +0000")
+
+	// A zone that ParseRssDate can't resolve is written as an offset
+	then = time.Date(1974, time.July, 23, 9, 10, 11, 0, time.FixedZone("CEST", 2*60*60))
+	testFormat(DateFormat{ZoneName: true}, "Tue, 23 Jul 1974 09:10:11 +0200")
+}
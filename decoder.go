@@ -0,0 +1,445 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// A Decoder reads an RSS 2.0 document one item at a time so that very large
+// feeds don't have to be held in memory. Typical usage is:
+//
+//	d := rssgo.NewDecoder(r)
+//	channel, err := d.Channel()
+//	...
+//	for d.Next() {
+//		if errs := d.ItemErr(); errs != nil {
+//			...
+//		}
+//		item := d.Item()
+//		...
+//	}
+//	if err := d.Err(); err != nil {
+//		...
+//	}
+type Decoder struct {
+	d       *xml.Decoder
+	rss     *Rss
+	item    *Item
+	itemErr ValidationErrors
+	next    *xml.StartElement
+	channel xml.Name
+	started bool
+	count   int
+	err     error
+}
+
+// Creates a Decoder that reads from r. The same character sets as
+// rssgo.Parse are supported.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{d: newXMLDecoder(r)}
+}
+
+// Returns the channel's metadata (everything but the items). Only the
+// elements that precede the first item are available until the items have
+// been read with Next, elements that follow the items are added to the same
+// Rss object as they're read. The Items field is never populated.
+func (d *Decoder) Channel() (*Rss, error) {
+	if !d.started {
+		d.started = true
+		d.rss = &Rss{}
+		if err := d.readHeader(); err != nil {
+			d.err = errors.New(fmt.Sprintf("Unable to parse the RSS document (%v)", err))
+		} else if d.next, err = d.readChannel(); err != nil {
+			d.err = errors.New(fmt.Sprintf("Unable to parse the RSS document (%v)", err))
+		}
+	}
+	return d.rss, d.err
+}
+
+// Reads the next item. Each item is checked with the same rules that
+// rssgo.Verify applies, an invalid item is still returned and ItemErr returns
+// its rssgo.ValidationErrors, so one bad item doesn't stop the decoding.
+// Returns false when there are no more items or an error occurred, see Err.
+func (d *Decoder) Next() bool {
+	d.item = nil
+	d.itemErr = nil
+	if _, err := d.Channel(); err != nil || d.next == nil {
+		return false
+	}
+
+	item := &Item{}
+	if err := d.d.DecodeElement(item, d.next); err != nil {
+		d.err = errors.New(fmt.Sprintf("Unable to parse item %v (%v)", d.count, err))
+		d.next = nil
+		return false
+	}
+
+	errs := verifyItem(d.count, item)
+
+	var err error
+	if d.next, err = d.readChannel(); err != nil {
+		d.err = errors.New(fmt.Sprintf("Unable to parse the RSS document (%v)", err))
+		return false
+	}
+
+	d.item = item
+	d.itemErr = errs
+	d.count++
+	return true
+}
+
+// Returns the item read by the last successful call to Next.
+func (d *Decoder) Item() *Item {
+	return d.item
+}
+
+// Returns the violations of the item read by the last successful call to
+// Next, or nil if it's valid.
+func (d *Decoder) ItemErr() ValidationErrors {
+	return d.itemErr
+}
+
+// Returns the first error encountered by Channel or Next. Invalid items aren't
+// errors, see ItemErr.
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+// Reads up to and including the channel's start element.
+func (d *Decoder) readHeader() error {
+	start, err := readRoot(d.d)
+	if err != nil {
+		return err
+	}
+	if start.Name.Local != "rss" {
+		return errors.New(fmt.Sprintf("Expecting an <rss> element but found <%v>", start.Name.Local))
+	}
+	readRssAttrs(d.rss, start.Attr)
+
+	start, err = d.nextStart()
+	if err != nil {
+		return err
+	}
+	if start.Name.Local != "channel" {
+		return errors.New(fmt.Sprintf("Expecting a <channel> element but found <%v>", start.Name.Local))
+	}
+	d.channel = start.Name
+	d.rss.ChannelAttrs = withoutNamespaceDecls(start.Attr)
+	return nil
+}
+
+// Returns the next start element, skipping any other tokens.
+func (d *Decoder) nextStart() (xml.StartElement, error) {
+	for {
+		tok, err := d.d.Token()
+		if err != nil {
+			return xml.StartElement{}, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return start.Copy(), nil
+		}
+	}
+}
+
+// Decodes channel elements until the next item's start element, which is
+// returned, or the end of the channel, when nil is returned.
+func (d *Decoder) readChannel() (*xml.StartElement, error) {
+	for {
+		tok, err := d.d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			start := t.Copy()
+			if start.Name.Local == "item" && start.Name.Space == d.channel.Space {
+				return &start, nil
+			}
+			if err := decodeChannelElement(d.d, d.rss, d.channel, &start); err != nil {
+				return nil, err
+			}
+		case xml.EndElement:
+			return nil, nil
+		}
+	}
+}
+
+// The names of the RSS 0.91 elements that were renamed in later versions
+var legacyNames = map[string]string{
+	"textinput": "textInput",
+}
+
+// Decodes a single child element of <channel> into the matching Rss field.
+// Unknown elements are added to the Rss's Extensions.
+func decodeChannelElement(d *xml.Decoder, rss *Rss, channel xml.Name, start *xml.StartElement) error {
+	if name, ok := legacyNames[start.Name.Local]; ok && start.Name.Space == channel.Space {
+		start.Name.Local = name
+	}
+
+	ok, err := decodeField(d, reflect.ValueOf(rss).Elem(), channelFields, channel, start)
+	if ok || err != nil {
+		return err
+	}
+	ok, err = decodeModule(d, &rss.Modules, false, channel, start)
+	if ok || err != nil {
+		return err
+	}
+
+	var el Element
+	if err := d.DecodeElement(&el, start); err != nil {
+		return err
+	}
+	rss.Extensions = append(rss.Extensions, el)
+	return nil
+}
+
+// Decodes a child element into the matching field of the struct v, appending
+// to slices. Returns false, without reading the element, if there's no
+// matching field.
+func decodeField(d *xml.Decoder, v reflect.Value, fields []elementField, parent xml.Name, start *xml.StartElement) (bool, error) {
+	// Extension fields, such as Rss.ITunes, are allocated when one of their
+	// elements is found
+	for _, field := range fields {
+		if field.extension == nil {
+			continue
+		}
+		f := v.Field(field.index)
+		reader, isReader := f.Interface().(wrapperReader)
+		if _, ok := findField(field.extension, parent, start.Name); ok || (isReader && reader.readsWrapper(start.Name)) {
+			if f.IsNil() {
+				f.Set(reflect.New(f.Type().Elem()))
+			}
+			if !ok {
+				return true, f.Interface().(wrapperReader).readWrapper(d, start)
+			}
+			return decodeField(d, f.Elem(), field.extension, parent, start)
+		}
+	}
+
+	field, ok := findField(fields, parent, start.Name)
+	if !ok {
+		return false, nil
+	}
+
+	f := v.Field(field.index)
+	if f.Kind() == reflect.Slice {
+		elem := reflect.New(f.Type().Elem())
+		if err := d.DecodeElement(elem.Interface(), start); err != nil {
+			return true, err
+		}
+		f.Set(reflect.Append(f, elem.Elem()))
+		return true, nil
+	}
+	return true, d.DecodeElement(f.Addr().Interface(), start)
+}
+
+// Implemented by extension structs whose fields may also be found inside a
+// wrapper element, such as the W3C <geo:Point>
+type wrapperReader interface {
+	// Returns true if the element is a wrapper. Called on nil pointers
+	readsWrapper(name xml.Name) bool
+
+	// Reads the wrapper element into the struct's fields
+	readWrapper(d *xml.Decoder, start *xml.StartElement) error
+}
+
+// A struct field that's stored in a child element
+type elementField struct {
+	index int
+	name  xml.Name
+
+	// The fields of an extension struct, whose elements are children of the
+	// same element as the struct's other fields. Set for fields tagged with
+	// ",extension"
+	extension []elementField
+}
+
+// The Rss fields stored in child elements of <channel>, not including Items
+// and Extensions
+var channelFields []elementField
+
+// The Item fields stored in child elements of <item>, not including
+// Extensions
+var itemFields []elementField
+
+// Returns the field for a child element of parent. A field without a
+// namespace matches the parent's namespace, so that extension elements such as
+// <itunes:author> aren't mistaken for the RSS elements.
+func findField(fields []elementField, parent xml.Name, name xml.Name) (elementField, bool) {
+	for _, field := range fields {
+		if field.extension != nil {
+			continue
+		}
+		space := field.name.Space
+		if space == "" {
+			space = parent.Space
+		}
+		if field.name.Local == name.Local && space == name.Space {
+			return field, true
+		}
+	}
+	return elementField{}, false
+}
+
+// Returns the fields of the struct t that are stored in elements whose path
+// starts with prefix, such as "channel>". Attributes, character data and the
+// ",any" fields are ignored. Fields tagged with ",extension" must be pointers
+// to structs.
+func elementFields(t reflect.Type, prefix string, ignore ...string) []elementField {
+	fields := []elementField{}
+	for i := 0; i != t.NumField(); i++ {
+		options := strings.Split(t.Field(i).Tag.Get("xml"), ",")
+		path := options[0]
+		field := elementField{index: i}
+		if space := strings.LastIndex(path, " "); space != -1 {
+			field.name.Space = path[:space]
+			path = path[space+1:]
+		}
+		if path == "" || path == "-" || !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		skip := false
+		for _, option := range options[1:] {
+			if option == "attr" || option == "any" || option == "chardata" ||
+				option == "innerxml" || option == "comment" {
+				skip = true
+			}
+			if option == "extension" {
+				field.extension = elementFields(t.Field(i).Type.Elem(), "")
+			}
+		}
+		for _, name := range ignore {
+			if t.Field(i).Name == name {
+				skip = true
+			}
+		}
+		if skip {
+			continue
+		}
+		field.name.Local = strings.TrimPrefix(path, prefix)
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// Sets the Version and Attrs from the <rss> element's attributes.
+func readRssAttrs(rss *Rss, attrs []xml.Attr) {
+	for _, attr := range attrs {
+		if attr.Name.Space == "" && attr.Name.Local == "version" {
+			rss.Version = attr.Value
+		} else {
+			rss.Attrs = append(rss.Attrs, attr)
+		}
+	}
+}
+
+// Reads an <rss> element. Elements that rssgo doesn't support are kept in the
+// Extensions of the Rss and its items.
+func (rss *Rss) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	if start.Name.Local != "rss" {
+		return errors.New(fmt.Sprintf("Expecting an <rss> element but found <%v>", start.Name.Local))
+	}
+	readRssAttrs(rss, start.Attr)
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local != "channel" || t.Name.Space != start.Name.Space {
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			rss.ChannelAttrs = append(rss.ChannelAttrs, withoutNamespaceDecls(t.Attr)...)
+			if err := rss.readChannel(d, t.Name); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// Reads the children of <channel> up to and including its end element.
+func (rss *Rss) readChannel(d *xml.Decoder, channel xml.Name) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "item" && t.Name.Space == channel.Space {
+				var item Item
+				if err := d.DecodeElement(&item, &t); err != nil {
+					return err
+				}
+				rss.Items = append(rss.Items, item)
+				continue
+			}
+			if err := decodeChannelElement(d, rss, channel, &t); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// Reads an <item> element. Elements that rssgo doesn't support are kept in
+// Extensions.
+func (item *Item) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	item.Attrs = append(item.Attrs, withoutNamespaceDecls(start.Attr)...)
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if err := decodeItemElement(d, item, start.Name, &t); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// Decodes a single child element of <item> into the matching Item field.
+// Unknown elements are added to the Item's Extensions.
+func decodeItemElement(d *xml.Decoder, item *Item, parent xml.Name, start *xml.StartElement) error {
+	ok, err := decodeField(d, reflect.ValueOf(item).Elem(), itemFields, parent, start)
+	if ok || err != nil {
+		return err
+	}
+	ok, err = decodeModule(d, &item.Modules, true, parent, start)
+	if ok || err != nil {
+		return err
+	}
+
+	var el Element
+	if err := d.DecodeElement(&el, start); err != nil {
+		return err
+	}
+	item.Extensions = append(item.Extensions, el)
+	return nil
+}
+
+func init() {
+	channelFields = elementFields(reflect.TypeOf(Rss{}), "channel>", "Items", "Modules", "Extensions")
+	itemFields = elementFields(reflect.TypeOf(Item{}), "", "Modules")
+}
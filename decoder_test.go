@@ -0,0 +1,100 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecoder(t *testing.T) {
+
+	doc := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>title</title>
+    <link>http://github.com/efarrer/rssgo/</link>
+    <description>A podcast</description>
+    <category>first</category>
+    <category domain="http://domain.com">second</category>
+    <unknown><nested/></unknown>
+    <cloud domain="example.com" port="80" path="/rpc" registerProcedure="ping" protocol="xml-rpc"/>
+    <item><title>one</title><guid isPermaLink="true">http://guid.com/1</guid></item>
+    <item><description>two</description></item>
+    <ttl>60</ttl>
+  </channel>
+</rss>`
+
+	d := NewDecoder(strings.NewReader(doc))
+	rss, err := d.Channel()
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) reading the channel\n", err)
+	}
+	if rss.Version != Version || rss.Title != "title" || rss.Description != "A podcast" {
+		t.Fatalf("Channel returned unexpected values %#v\n", rss)
+	}
+	if len(rss.Categories) != 2 || rss.Categories[1].Domain != "http://domain.com" {
+		t.Fatalf("Channel returned unexpected categories %#v\n", rss.Categories)
+	}
+	if rss.Cloud == nil || rss.Cloud.Port != 80 {
+		t.Fatalf("Channel returned unexpected cloud %#v\n", rss.Cloud)
+	}
+
+	titles := []string{}
+	for d.Next() {
+		item := d.Item()
+		titles = append(titles, item.Title+item.Description)
+	}
+	if err := d.Err(); err != nil {
+		t.Fatalf("Unexpected error (%v) reading the items\n", err)
+	}
+	if strings.Join(titles, ",") != "one,two" {
+		t.Fatalf("Next returned unexpected items %v\n", titles)
+	}
+	if rss.Ttl != 60 || len(rss.Items) != 0 {
+		t.Fatalf("Elements after the items should be added to the channel %#v\n", rss)
+	}
+	if d.Next() || d.Item() != nil {
+		t.Fatalf("Next should return false after the last item\n")
+	}
+
+	// Invalid items
+	doc = `<rss version="2.0"><channel><title>title</title>
+<item><title>one</title></item><item></item><item><title>three</title></item>
+</channel></rss>`
+	d = NewDecoder(strings.NewReader(doc))
+	titles = []string{}
+	invalid := []int{}
+	for d.Next() {
+		if errs := d.ItemErr(); errs != nil {
+			if errs[0].Field != "Items[1].Title" {
+				t.Fatalf("Unexpected item errors %v\n", errs)
+			}
+			invalid = append(invalid, len(titles))
+		}
+		titles = append(titles, d.Item().Title)
+	}
+	if d.Err() != nil || strings.Join(titles, ",") != "one,,three" || len(invalid) != 1 || invalid[0] != 1 {
+		t.Fatalf("An invalid item should be reported without stopping the decoding (%v) %v %v\n",
+			d.Err(), titles, invalid)
+	}
+
+	// Malformed documents
+	for _, doc := range []string{
+		"",
+		`<feed><channel/></feed>`,
+		`<rss version="2.0"><item/></rss>`,
+		`<rss version="2.0"><channel><title>title</title>`,
+		`<rss version="2.0"><channel><ttl>sixty</ttl></channel></rss>`,
+	} {
+		d = NewDecoder(strings.NewReader(doc))
+		if _, err := d.Channel(); err == nil {
+			t.Fatalf("Channel should fail on %v\n", doc)
+		}
+		if d.Next() || d.Err() == nil {
+			t.Fatalf("Next should fail on %v\n", doc)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The namespace of the Dublin Core elements, see
+// http://dublincore.org/documents/dces/
+const DublinCoreNamespace = "http://purl.org/dc/elements/1.1/"
+
+// The Dublin Core elements of a channel or item
+type DublinCore struct {
+	// Optional. The people or organizations that created the resource
+	Creators []string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+
+	// Optional. The date of the resource. See rssgo.NewW3CTime and
+	// rssgo.W3CTimeFromString
+	Date W3CTime `xml:"http://purl.org/dc/elements/1.1/ date"`
+
+	// Optional. The topics of the resource
+	Subjects []string `xml:"http://purl.org/dc/elements/1.1/ subject"`
+
+	// Optional. The rights held in and over the resource
+	Rights string `xml:"http://purl.org/dc/elements/1.1/ rights,omitempty"`
+}
+
+// Writes the elements without an enclosing element.
+func (dc DublinCore) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type plain DublinCore
+	return marshalFlat(e, plain(dc))
+}
+
+// Returns the item's author, or its Dublin Core creators if the author isn't
+// set.
+func (item *Item) EffectiveAuthor() string {
+	if strings.TrimSpace(item.Author) != "" || item.DC == nil {
+		return item.Author
+	}
+	return strings.Join(item.DC.Creators, ", ")
+}
+
+// Returns the item's publication date, or its Dublin Core date if the
+// publication date isn't set or can't be parsed.
+func (item *Item) EffectivePubDate() time.Time {
+	if !item.PubDate.Time.IsZero() || item.DC == nil {
+		return item.PubDate.Time
+	}
+	return item.DC.Date.Time
+}
+
+// Verifies the Dublin Core elements.
+func (v *verifier) verifyDublinCore(path string, dc *DublinCore) {
+	for i := 0; i != len(dc.Creators); i++ {
+		if strings.TrimSpace(dc.Creators[i]) == "" {
+			v.add(fmt.Sprintf("%v.Creators[%v]", path, i), CodeRequired, "Dublin Core creator should not be empty.")
+		}
+	}
+
+	// A changed Time is written as a W3C date so only the text is checked
+	if dc.Date.Text != "" && !dc.Date.changed() {
+		if _, err := ParseW3CDate(dc.Date.Text); err != nil {
+			v.add(path+".Date", CodeDate, fmt.Sprintf("Unable to parse the Dublin Core date as a W3C date (%v)", err))
+		}
+	}
+
+	for i := 0; i != len(dc.Subjects); i++ {
+		if strings.TrimSpace(dc.Subjects[i]) == "" {
+			v.add(fmt.Sprintf("%v.Subjects[%v]", path, i), CodeRequired, "Dublin Core subject should not be empty.")
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDublinCore(t *testing.T) {
+
+	doc := `<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/"><channel>
+<title>title</title><link>http://github.com/efarrer/rssgo/</link><description>A journal</description>
+<dc:rights>Copyright 1974</dc:rights>
+<item><title>one</title>
+<dc:creator>Jane Doe</dc:creator><dc:creator>John Roe</dc:creator>
+<dc:date>1974-07-23T09:10:11Z</dc:date>
+<dc:subject>Physics</dc:subject><dc:subject>Optics</dc:subject>
+</item>
+<item><title>two</title><author>author@example.com</author>
+<pubDate>Wed, 24 Jul 1974 09:10:11 GMT</pubDate>
+<dc:creator>Jane Doe</dc:creator><dc:date>1974-07-23</dc:date></item>
+<item><title>three</title></item>
+</channel></rss>`
+
+	rss, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing the document\n", err)
+	}
+	if rss.DC == nil || rss.DC.Rights != "Copyright 1974" || len(rss.Extensions) != 0 {
+		t.Fatalf("Unexpected channel Dublin Core %#v\n", rss.DC)
+	}
+	dc := rss.Items[0].DC
+	if dc == nil || len(dc.Creators) != 2 || dc.Creators[1] != "John Roe" ||
+		dc.Date.Text != "1974-07-23T09:10:11Z" || len(dc.Subjects) != 2 || dc.Subjects[0] != "Physics" {
+		t.Fatalf("Unexpected item Dublin Core %#v\n", dc)
+	}
+	if err := Verify(rss); err != nil {
+		t.Fatalf("Unexpected verify error %v\n", err)
+	}
+
+	// The helpers fall back to the Dublin Core elements
+	expected := time.Date(1974, time.July, 23, 9, 10, 11, 0, time.UTC)
+	if a := rss.Items[0].EffectiveAuthor(); a != "Jane Doe, John Roe" {
+		t.Fatalf("Unexpected author %v\n", a)
+	}
+	if d := rss.Items[0].EffectivePubDate(); !d.Equal(expected) {
+		t.Fatalf("Unexpected date %v\n", d)
+	}
+	if a := rss.Items[1].EffectiveAuthor(); a != "author@example.com" {
+		t.Fatalf("Unexpected author %v\n", a)
+	}
+	if d := rss.Items[1].EffectivePubDate(); !d.Equal(expected.Add(24 * time.Hour)) {
+		t.Fatalf("Unexpected date %v\n", d)
+	}
+	if a, d := rss.Items[2].EffectiveAuthor(), rss.Items[2].EffectivePubDate(); a != "" || !d.IsZero() {
+		t.Fatalf("Unexpected author %v or date %v\n", a, d)
+	}
+
+	data, err := xml.Marshal(rss)
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) marshalling the document\n", err)
+	}
+	for _, text := range []string{
+		`<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">`,
+		`<description>A journal</description><dc:rights>Copyright 1974</dc:rights><item>`,
+		`<dc:creator>Jane Doe</dc:creator><dc:creator>John Roe</dc:creator><dc:date>1974-07-23T09:10:11Z</dc:date><dc:subject>Physics</dc:subject><dc:subject>Optics</dc:subject>`,
+		`<item><title>three</title></item>`,
+	} {
+		if !strings.Contains(string(data), text) {
+			t.Fatalf("Marshalled document is missing %v: %v\n", text, string(data))
+		}
+	}
+
+	// New dates are written as W3C dates
+	rss.Items[2].DC = &DublinCore{Date: NewW3CTime(expected)}
+	data, err = xml.Marshal(rss)
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) marshalling the document\n", err)
+	}
+	if !strings.Contains(string(data), `<item><title>three</title><dc:date>1974-07-23T09:10:11Z</dc:date></item>`) {
+		t.Fatalf("Unexpected marshalled document %v\n", string(data))
+	}
+
+	// Violations
+	rss.Items[2].DC = &DublinCore{Creators: []string{" "}, Date: W3CTimeFromString("Tue, 23 Jul 1974 09:10:11 GMT"),
+		Subjects: []string{""}}
+	errs := VerifyAll(rss)
+	if len(errs) != 3 || errs[0].Field != "Items[2].DC.Creators[0]" || errs[1].Field != "Items[2].DC.Date" ||
+		errs[1].Code != CodeDate || errs[2].Field != "Items[2].DC.Subjects[0]" {
+		t.Fatalf("Unexpected verify errors %v\n", errs)
+	}
+
+	// A changed date is written as a W3C date so it's valid
+	rss.Items[2].DC = &DublinCore{Date: W3CTimeFromString("Tue, 23 Jul 1974 09:10:11 GMT")}
+	rss.Items[2].DC.Date.Time = expected.Add(time.Hour)
+	if errs := VerifyAll(rss); errs != nil {
+		t.Fatalf("Unexpected verify errors for a changed date %v\n", errs)
+	}
+}
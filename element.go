@@ -0,0 +1,116 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// An XML element that rssgo doesn't model, such as an element from an
+// extension namespace. Elements are kept when a feed is parsed and written back
+// unchanged.
+type Element struct {
+	// The element's namespace URI and local name
+	XMLName xml.Name
+
+	// The element's attributes, not including namespace declarations
+	Attrs []xml.Attr
+
+	// The element's content. The tokens are xml.StartElement,
+	// xml.EndElement, xml.CharData, xml.Comment and xml.ProcInst values.
+	Tokens []xml.Token
+}
+
+// Returns the element's character data, not including that of nested
+// elements, with leading and trailing white space removed.
+func (el *Element) Text() string {
+	text := ""
+	depth := 0
+	for _, tok := range el.Tokens {
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			if depth == 0 {
+				text += string(t)
+			}
+		}
+	}
+	return strings.TrimSpace(text)
+}
+
+// Writes the element. The start element's name is ignored in favor of
+// XMLName. Namespaces are given prefixes when the element is written as part
+// of a Rss.
+func (el Element) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{Name: el.XMLName, Attr: el.Attrs}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, tok := range el.Tokens {
+		// xml.Encoder writes namespaces as default namespace declarations,
+		// which nested elements without a namespace would otherwise inherit
+		if child, ok := tok.(xml.StartElement); ok && child.Name.Space == "" {
+			child.Attr = append([]xml.Attr{{Name: xml.Name{Local: "xmlns"}}},
+				child.Attr...)
+			tok = child
+		}
+		if err := e.EncodeToken(tok); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// Reads the element and its content.
+func (el *Element) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	el.XMLName = start.Name
+	el.Attrs = withoutNamespaceDecls(start.Attr)
+	el.Tokens = nil
+
+	depth := 0
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			t = t.Copy()
+			t.Attr = withoutNamespaceDecls(t.Attr)
+			tok = t
+		case xml.EndElement:
+			if depth == 0 {
+				return nil
+			}
+			depth--
+		case xml.Directive:
+			continue
+		default:
+			tok = xml.CopyToken(tok)
+		}
+		el.Tokens = append(el.Tokens, tok)
+	}
+}
+
+// Returns true if the attribute declares a namespace.
+func isNamespaceDecl(attr xml.Attr) bool {
+	return attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns")
+}
+
+// Returns the attributes that aren't namespace declarations.
+func withoutNamespaceDecls(attrs []xml.Attr) []xml.Attr {
+	var filtered []xml.Attr
+	for _, attr := range attrs {
+		if !isNamespaceDecl(attr) {
+			filtered = append(filtered, attr)
+		}
+	}
+	return filtered
+}
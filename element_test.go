@@ -0,0 +1,165 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const extensionsDoc = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd"
+	xmlns:a="http://example.com/a" xmlns:b="http://example.com/b"
+	xmlns:x="http://example.com/unused" xml:lang="en">
+<channel a:id="main" custom="yes">
+<title>title</title>
+<link>http://github.com/efarrer/rssgo/</link>
+<description>A podcast</description>
+<a:link href="http://github.com/efarrer/rssgo/feed" rel="self" type="application/rss+xml"/>
+<a:author>The author</a:author>
+<a:category text="Technology"><a:category text="Podcasting"/></a:category>
+<custom>Not namespaced<nested>text</nested></custom>
+<item custom="yes">
+<title>item</title>
+<a:title>The a title</a:title>
+<b:creator>The creator</b:creator>
+<a:content url="http://example.com/a.mp3" xmlns:p="http://example.com/private" p:key="value"><p:thing>x</p:thing></a:content>
+</item>
+</channel>
+</rss>`
+
+func TestExtensions(t *testing.T) {
+
+	rss, err := Parse(strings.NewReader(extensionsDoc))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing the document\n", err)
+	}
+
+	if rss.Version != "2.0" || rss.Title != "title" || len(rss.Extensions) != 4 {
+		t.Fatalf("Unexpected channel %#v\n", rss)
+	}
+	if rss.Extensions[1].XMLName.Space != "http://example.com/a" ||
+		rss.Extensions[1].Text() != "The author" {
+		t.Fatalf("Unexpected a:author %#v\n", rss.Extensions[1])
+	}
+	if rss.Extensions[3].Text() != "Not namespaced" {
+		t.Fatalf("Unexpected Text() %v\n", rss.Extensions[3].Text())
+	}
+
+	// Extension elements with the same names as item elements don't replace
+	// them
+	item := rss.Items[0]
+	if item.Title != "item" || len(item.Extensions) != 3 ||
+		item.Extensions[0].Text() != "The a title" {
+		t.Fatalf("Unexpected item %#v\n", item)
+	}
+	if len(item.Attrs) != 1 || item.Attrs[0].Value != "yes" {
+		t.Fatalf("Unexpected item attributes %#v\n", item.Attrs)
+	}
+	if len(rss.ChannelAttrs) != 2 || rss.ChannelAttrs[0].Name.Space != "http://example.com/a" ||
+		rss.ChannelAttrs[1].Value != "yes" {
+		t.Fatalf("Unexpected channel attributes %#v\n", rss.ChannelAttrs)
+	}
+
+	data, err := xml.Marshal(rss)
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) marshalling the document\n", err)
+	}
+	for _, text := range []string{
+		`xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd"`,
+		`xmlns:b="http://example.com/b"`,
+		`xmlns:x="http://example.com/unused"`,
+		`xml:lang="en"`,
+		`<channel a:id="main" custom="yes">`,
+		`<a:link href="http://github.com/efarrer/rssgo/feed" rel="self" type="application/rss+xml"></a:link>`,
+		`<a:category text="Technology"><a:category text="Podcasting"></a:category></a:category>`,
+		`<custom>Not namespaced<nested>text</nested></custom>`,
+		`<item custom="yes">`,
+		`<a:title>The a title</a:title>`,
+		`<b:creator>The creator</b:creator>`,
+		`xmlns:ns1="http://example.com/private"`,
+		`<a:content url="http://example.com/a.mp3" ns1:key="value"><ns1:thing>x</ns1:thing></a:content>`,
+	} {
+		if !strings.Contains(string(data), text) {
+			t.Fatalf("Marshalled document is missing %v: %v\n", text, string(data))
+		}
+	}
+	if strings.Contains(string(data), `xmlns="`) {
+		t.Fatalf("Marshalled document has default namespaces: %v\n", string(data))
+	}
+
+	// The marshalled document parses to the same Rss
+	reparsed, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing the marshalled document\n", err)
+	}
+	if !reflect.DeepEqual(rss.Extensions, reparsed.Extensions) ||
+		!reflect.DeepEqual(rss.ChannelAttrs, reparsed.ChannelAttrs) ||
+		!reflect.DeepEqual(rss.Items[0].Extensions, reparsed.Items[0].Extensions) {
+		t.Fatalf("Extensions changed\n%#v\n%#v\n", rss, reparsed)
+	}
+
+	// Undeclared namespaces get the usual prefixes
+	rss = &Rss{Version: Version, Title: "title", Link: "http://github.com/efarrer/rssgo/",
+		Description: "A podcast",
+		Items: []Item{{Title: "item", Extensions: []Element{{
+			XMLName: xml.Name{Space: "http://purl.org/dc/elements/1.1/", Local: "creator"},
+			Tokens:  []xml.Token{xml.CharData("<Evan>")}}, {
+			XMLName: xml.Name{Space: "http://example.com/", Local: "other"}}}}}}
+	data, err = xml.Marshal(rss)
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) marshalling the document\n", err)
+	}
+	expected := `<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:ns1="http://example.com/">`
+	if !strings.HasPrefix(string(data), expected) ||
+		!strings.Contains(string(data), "<dc:creator>&lt;Evan&gt;</dc:creator><ns1:other></ns1:other>") {
+		t.Fatalf("Unexpected marshalled document: %v\n", string(data))
+	}
+
+	// The streaming Decoder and Encoder keep the extensions too
+	d := NewDecoder(strings.NewReader(extensionsDoc))
+	channel, err := d.Channel()
+	if err != nil || len(channel.Extensions) != 4 || len(channel.ChannelAttrs) != 2 {
+		t.Fatalf("Unexpected channel (%v) %#v\n", err, channel)
+	}
+	if !d.Next() || len(d.Item().Extensions) != 3 {
+		t.Fatalf("Unexpected item (%v) %#v\n", d.Err(), d.Item())
+	}
+
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+	if err := e.WriteHeader(channel); err != nil {
+		t.Fatalf("Unexpected error (%v) writing the header\n", err)
+	}
+	if err := e.WriteItem(*d.Item()); err != nil {
+		t.Fatalf("Unexpected error (%v) writing the item\n", err)
+	}
+	item.Extensions = append(item.Extensions, Element{
+		XMLName: xml.Name{Space: "http://example.com/", Local: "other"}})
+	if err := e.WriteItem(item); err != nil {
+		t.Fatalf("Unexpected error (%v) writing the item\n", err)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatalf("Unexpected error (%v) closing the encoder\n", err)
+	}
+	for _, text := range []string{
+		`xmlns:a="http://example.com/a"`,
+		`<channel a:id="main" custom="yes">`,
+		`<a:author>The author</a:author>`,
+		`<a:title>The a title</a:title>`,
+		`<ns1:other xmlns:ns1="http://example.com/"></ns1:other>`,
+	} {
+		if !strings.Contains(buf.String(), text) {
+			t.Fatalf("Encoded document is missing %v: %v\n", text, buf.String())
+		}
+	}
+	reparsed, err = Parse(&buf)
+	if err != nil || len(reparsed.Items) != 2 || len(reparsed.Items[1].Extensions) != 4 {
+		t.Fatalf("Unexpected encoded document (%v) %#v\n", err, reparsed)
+	}
+}
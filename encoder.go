@@ -0,0 +1,170 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+)
+
+// An Encoder writes an RSS 2.0 document one item at a time so that very large
+// feeds don't have to be held in memory. Typical usage is:
+//
+//	e := rssgo.NewEncoder(w)
+//	e.Declare(rssgo.ITunesNamespace)
+//	if err := e.WriteHeader(channel); err != nil {
+//		...
+//	}
+//	for ... {
+//		if err := e.WriteItem(item); err != nil {
+//			...
+//		}
+//	}
+//	if err := e.Close(); err != nil {
+//		...
+//	}
+type Encoder struct {
+	w      io.Writer
+	e      *xml.Encoder
+	ns     *nsWriter
+	spaces []string
+	header bool
+	closed bool
+	count  int
+}
+
+// Creates an Encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	e := xml.NewEncoder(w)
+	return &Encoder{w: w, e: e, ns: newNSWriter(e)}
+}
+
+// Sets the indentation used for the document, see xml.Encoder.Indent. Must be
+// called before WriteHeader.
+func (e *Encoder) Indent(prefix, indent string) {
+	e.e.Indent(prefix, indent)
+}
+
+// Declares namespaces on the <rss> element, such as rssgo.ITunesNamespace, so
+// that the items that use them don't each declare them. The namespaces that
+// the channel uses are always declared there. Well known and registered
+// namespaces use their usual prefixes. Must be called before WriteHeader.
+func (e *Encoder) Declare(namespaces ...string) {
+	e.spaces = append(e.spaces, namespaces...)
+}
+
+// Writes the XML declaration, the <rss> element and the channel's metadata.
+// The channel is checked with rssgo.Verify first. The Items field is
+// ignored, items are written with WriteItem.
+func (e *Encoder) WriteHeader(rss *Rss) error {
+	if e.header {
+		return errors.New("The header has already been written.")
+	}
+
+	channel := *rss
+	channel.Items = nil
+	if err := Verify(&channel); err != nil {
+		return err
+	}
+
+	tokens, err := marshalTokens(&channel)
+	if err != nil {
+		return err
+	}
+
+	// Nothing has been encoded yet so it's safe to bypass the xml.Encoder
+	if _, err := io.WriteString(e.w, xml.Header); err != nil {
+		return err
+	}
+
+	// Keep the prefixes declared on the <rss> element, and add the
+	// namespaces declared for the items
+	if root, ok := tokens[0].(xml.StartElement); ok {
+		e.ns.declareAttrs(root.Attr)
+	}
+	for _, uri := range e.spaces {
+		e.ns.use(uri)
+	}
+
+	// Leave the </channel> and </rss> elements open for the items
+	for _, tok := range tokens[:len(tokens)-2] {
+		if err := e.ns.EncodeToken(tok); err != nil {
+			return err
+		}
+	}
+
+	e.header = true
+	return nil
+}
+
+// Writes an item. Each item is checked with the same rules that rssgo.Verify
+// applies, the rssgo.ValidationErrors are returned for an invalid item.
+func (e *Encoder) WriteItem(item Item) error {
+	if !e.header || e.closed {
+		return errors.New("Items can only be written after the header and before Close.")
+	}
+
+	if errs := verifyItem(e.count, &item); errs != nil {
+		return errs
+	}
+
+	tokens, err := marshalTokens(itemElement{Item: item})
+	if err != nil {
+		return err
+	}
+	for _, tok := range tokens {
+		if err := e.ns.EncodeToken(tok); err != nil {
+			return err
+		}
+	}
+
+	e.count++
+	return nil
+}
+
+// Closes the <channel> and <rss> elements and flushes the document.
+func (e *Encoder) Close() error {
+	if !e.header || e.closed {
+		return errors.New("Close can only be called once after the header is written.")
+	}
+
+	for _, name := range []string{"channel", "rss"} {
+		if err := e.ns.EncodeToken(xml.EndElement{Name: xml.Name{Local: name}}); err != nil {
+			return err
+		}
+	}
+
+	e.closed = true
+	return e.e.Flush()
+}
+
+// Marshals an Item as an <item> element
+type itemElement struct {
+	XMLName xml.Name `xml:"item"`
+	Item
+}
+
+// Marshals v and returns the XML tokens for the result.
+func marshalTokens(v interface{}) ([]xml.Token, error) {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := []xml.Token{}
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return tokens, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, xml.CopyToken(tok))
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestEncoder(t *testing.T) {
+
+	createValidRss := func() *Rss {
+		return &Rss{Version: Version,
+			Title:       "title",
+			Link:        "http://github.com/efarrer/rssgo/",
+			Description: "A podcast",
+			Categories:  []Category{{Category: "category"}},
+			Ttl:         60,
+			Items:       []Item{{Title: "ignored"}}}
+	}
+
+	buf := &bytes.Buffer{}
+	e := NewEncoder(buf)
+	e.Indent("", "  ")
+	if err := e.WriteHeader(createValidRss()); err != nil {
+		t.Fatalf("Unexpected error (%v) writing the header\n", err)
+	}
+	for _, title := range []string{"one", "two", "three"} {
+		if err := e.WriteItem(Item{Title: title, Link: "http://link.com/" + title}); err != nil {
+			t.Fatalf("Unexpected error (%v) writing item %v\n", err, title)
+		}
+	}
+	if err := e.WriteItem(Item{}); err == nil {
+		t.Fatalf("WriteItem should fail on an item without a title or description\n")
+	}
+	if err := e.Close(); err != nil {
+		t.Fatalf("Unexpected error (%v) closing the encoder\n", err)
+	}
+	if err := e.Close(); err == nil {
+		t.Fatalf("Close should fail when called twice\n")
+	}
+	if err := e.WriteItem(Item{Title: "late"}); err == nil {
+		t.Fatalf("WriteItem should fail after Close\n")
+	}
+
+	if !strings.HasPrefix(buf.String(), `<?xml version="1.0" encoding="UTF-8"?>`) {
+		t.Fatalf("The document should start with an XML declaration %v\n", buf.String())
+	}
+	if strings.Contains(buf.String(), "ignored") {
+		t.Fatalf("The header's items should not be written %v\n", buf.String())
+	}
+
+	// Only the namespaces that the channel uses are declared on the <rss>
+	// element, the same as xml.Marshal
+	data, err := xml.Marshal(createValidRss())
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) marshalling the channel\n", err)
+	}
+	root := string(data[:bytes.IndexByte(data, '>')+1])
+	if !strings.Contains(buf.String(), root) {
+		t.Fatalf("Expected the <rss> element %v %v\n", root, buf.String())
+	}
+
+	rss, err := ParseWithOptions(buf, ParseOptions{Verify: true})
+	if err != nil {
+		t.Fatalf("Unable to parse the encoded document (%v)\n", err)
+	}
+	if rss.Title != "title" || rss.Ttl != 60 || len(rss.Categories) != 1 {
+		t.Fatalf("Encoded channel has unexpected values %#v\n", rss)
+	}
+	if len(rss.Items) != 3 || rss.Items[2].Title != "three" {
+		t.Fatalf("Encoded document has unexpected items %#v\n", rss.Items)
+	}
+
+	// The namespaces the items use are declared once on the <rss> element
+	// when the caller declares them
+	buf = &bytes.Buffer{}
+	e = NewEncoder(buf)
+	e.Declare(ContentNamespace, ITunesNamespace)
+	if err := e.WriteHeader(createValidRss()); err != nil {
+		t.Fatalf("Unexpected error (%v) writing the header\n", err)
+	}
+	for _, title := range []string{"one", "two"} {
+		item := Item{Title: title, Description: title, Content: "<p>" + title + "</p>",
+			ITunes: &ITunesItem{Episode: "1"}}
+		if err := e.WriteItem(item); err != nil {
+			t.Fatalf("Unexpected error (%v) writing item %v\n", err, title)
+		}
+	}
+	if err := e.Close(); err != nil {
+		t.Fatalf("Unexpected error (%v) closing the encoder\n", err)
+	}
+	for _, decl := range []string{`xmlns:content="` + ContentNamespace + `"`, `xmlns:itunes="` + ITunesNamespace + `"`} {
+		if strings.Count(buf.String(), decl) != 1 || !strings.Contains(buf.String()[:strings.Index(buf.String(), "<channel>")], decl) {
+			t.Fatalf("Expected %v to be declared once on the <rss> element %v\n", decl, buf.String())
+		}
+	}
+	rss, err = Parse(buf)
+	if err != nil || len(rss.Items) != 2 || rss.Items[1].Content != "<p>two</p>" || rss.Items[1].ITunes.Episode != "1" {
+		t.Fatalf("Unexpected encoded document (%v) %#v\n", err, rss)
+	}
+
+	// Invalid headers
+	invalid := createValidRss()
+	invalid.Title = ""
+	if err := NewEncoder(&bytes.Buffer{}).WriteHeader(invalid); err == nil {
+		t.Fatalf("WriteHeader should fail on an invalid channel\n")
+	}
+
+	// Out of order calls
+	e = NewEncoder(&bytes.Buffer{})
+	if err := e.WriteItem(Item{Title: "early"}); err == nil {
+		t.Fatalf("WriteItem should fail before WriteHeader\n")
+	}
+	if err := e.Close(); err == nil {
+		t.Fatalf("Close should fail before WriteHeader\n")
+	}
+	if err := e.WriteHeader(createValidRss()); err != nil {
+		t.Fatalf("Unexpected error (%v) writing the header\n", err)
+	}
+	if err := e.WriteHeader(createValidRss()); err == nil {
+		t.Fatalf("WriteHeader should fail when called twice\n")
+	}
+}
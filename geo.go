@@ -0,0 +1,257 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// The namespace of the GeoRSS Simple elements, see http://www.georss.org/simple
+const GeoRSSNamespace = "http://www.georss.org/georss"
+
+// The namespace of the W3C Basic Geo vocabulary, see
+// http://www.w3.org/2003/01/geo/
+const GeoNamespace = "http://www.w3.org/2003/01/geo/wgs84_pos#"
+
+// The mean radius of the Earth in kilometres, used by rssgo.Distance
+const EarthRadius = 6371.0
+
+// The geographic elements of a channel or item. Coordinates are kept as text
+// so that invalid values survive parsing; see rssgo.ParseGeoPoints
+type GeoLocation struct {
+	// Optional. A latitude and longitude, for example "45.256 -71.92"
+	Point string `xml:"http://www.georss.org/georss point,omitempty"`
+
+	// Optional. Two or more points of a line
+	Line string `xml:"http://www.georss.org/georss line,omitempty"`
+
+	// Optional. The lower and upper corners of a box, for example
+	// "42.943 -71.032 43.039 -69.856"
+	Box string `xml:"http://www.georss.org/georss box,omitempty"`
+
+	// Optional. The W3C latitude, set together with Long. It's also read from
+	// a <geo:Point> wrapper, but it's always written without one
+	Lat string `xml:"http://www.w3.org/2003/01/geo/wgs84_pos# lat,omitempty"`
+
+	// Optional. The W3C longitude, set together with Lat
+	Long string `xml:"http://www.w3.org/2003/01/geo/wgs84_pos# long,omitempty"`
+}
+
+// Returns true for the W3C <geo:Point> element, which wraps geo:lat and
+// geo:long.
+func (g *GeoLocation) readsWrapper(name xml.Name) bool {
+	return name.Space == GeoNamespace && name.Local == "Point"
+}
+
+// Reads the geo:lat and geo:long of a <geo:Point> into Lat and Long, unless
+// they're already set. The point's other elements, such as geo:alt, are
+// dropped.
+func (g *GeoLocation) readWrapper(d *xml.Decoder, start *xml.StartElement) error {
+	var point struct {
+		Lat  string `xml:"http://www.w3.org/2003/01/geo/wgs84_pos# lat"`
+		Long string `xml:"http://www.w3.org/2003/01/geo/wgs84_pos# long"`
+	}
+	if err := d.DecodeElement(&point, start); err != nil {
+		return err
+	}
+	if g.Lat == "" && g.Long == "" {
+		g.Lat, g.Long = point.Lat, point.Long
+	}
+	return nil
+}
+
+// A WGS84 latitude and longitude in decimal degrees
+type GeoPoint struct {
+	Lat  float64
+	Long float64
+}
+
+// An area between two corners. When Lower.Long is greater than Upper.Long the
+// box crosses the 180th meridian
+type GeoBox struct {
+	Lower GeoPoint
+	Upper GeoPoint
+}
+
+// Writes the elements without an enclosing element.
+func (g GeoLocation) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type plain GeoLocation
+	return marshalFlat(e, plain(g))
+}
+
+// Parses whitespace separated latitude and longitude pairs.
+func ParseGeoPoints(text string) ([]GeoPoint, error) {
+	values := strings.Fields(text)
+	if len(values) == 0 || len(values)%2 != 0 {
+		return nil, errors.New(fmt.Sprintf("Expecting latitude and longitude pairs in %#v", text))
+	}
+	points := make([]GeoPoint, 0, len(values)/2)
+	for i := 0; i != len(values); i += 2 {
+		point, err := parseGeoPoint(values[i], values[i+1])
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+// Parses a georss:box value.
+func ParseGeoBox(text string) (GeoBox, error) {
+	points, err := ParseGeoPoints(text)
+	if err != nil {
+		return GeoBox{}, err
+	}
+	if len(points) != 2 {
+		return GeoBox{}, errors.New(fmt.Sprintf("Expecting two corners in %#v", text))
+	}
+	if points[0].Lat > points[1].Lat {
+		return GeoBox{}, errors.New(fmt.Sprintf("The lower corner is north of the upper corner in %#v", text))
+	}
+	return GeoBox{points[0], points[1]}, nil
+}
+
+// Parses and range checks a latitude and longitude.
+func parseGeoPoint(lat, long string) (GeoPoint, error) {
+	var point GeoPoint
+	var err error
+	if point.Lat, err = strconv.ParseFloat(strings.TrimSpace(lat), 64); err != nil || !inRange(point.Lat, 90) {
+		return GeoPoint{}, errors.New(fmt.Sprintf("Bad latitude %#v. Expecting -90 to 90", lat))
+	}
+	if point.Long, err = strconv.ParseFloat(strings.TrimSpace(long), 64); err != nil || !inRange(point.Long, 180) {
+		return GeoPoint{}, errors.New(fmt.Sprintf("Bad longitude %#v. Expecting -180 to 180", long))
+	}
+	return point, nil
+}
+
+// Returns true if x is a number from -limit to limit. strconv.ParseFloat
+// accepts NaN and Inf, which aren't coordinates.
+func inRange(x, limit float64) bool {
+	return !math.IsNaN(x) && !math.IsInf(x, 0) && math.Abs(x) <= limit
+}
+
+// Returns a single point for the location and true, or false if it has no
+// valid coordinates. The georss:point is used first, then geo:lat and
+// geo:long, then the center of the georss:box and then the start of the
+// georss:line.
+func (g *GeoLocation) Location() (GeoPoint, bool) {
+	if points, err := ParseGeoPoints(g.Point); err == nil && len(points) == 1 {
+		return points[0], true
+	}
+	if point, err := parseGeoPoint(g.Lat, g.Long); err == nil {
+		return point, true
+	}
+	if box, err := ParseGeoBox(g.Box); err == nil {
+		return box.Center(), true
+	}
+	if points, err := ParseGeoPoints(g.Line); err == nil {
+		return points[0], true
+	}
+	return GeoPoint{}, false
+}
+
+// Returns the item's location and true, or false if the item has no valid
+// coordinates. See GeoLocation.Location
+func (item *Item) Location() (GeoPoint, bool) {
+	if item.Geo == nil {
+		return GeoPoint{}, false
+	}
+	return item.Geo.Location()
+}
+
+// Returns true if the point is inside the box or on its edge.
+func (box GeoBox) Contains(point GeoPoint) bool {
+	if point.Lat < box.Lower.Lat || point.Lat > box.Upper.Lat {
+		return false
+	}
+	if box.Lower.Long <= box.Upper.Long {
+		return point.Long >= box.Lower.Long && point.Long <= box.Upper.Long
+	}
+	return point.Long >= box.Lower.Long || point.Long <= box.Upper.Long
+}
+
+// Returns the point halfway between the box's corners.
+func (box GeoBox) Center() GeoPoint {
+	long := (box.Lower.Long + box.Upper.Long) / 2
+	if box.Lower.Long > box.Upper.Long {
+		long += 180
+		if long > 180 {
+			long -= 360
+		}
+	}
+	return GeoPoint{(box.Lower.Lat + box.Upper.Lat) / 2, long}
+}
+
+// Returns the great-circle distance between the points in kilometres.
+func Distance(a, b GeoPoint) float64 {
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLong := (b.Long - a.Long) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLong/2)*math.Sin(dLong/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Returns the items whose location is inside the box. Items without a
+// location are skipped.
+func (rss *Rss) ItemsInBox(box GeoBox) []Item {
+	return rss.filterItems(func(point GeoPoint) bool {
+		return box.Contains(point)
+	})
+}
+
+// Returns the items whose location is within km kilometres of the point.
+// Items without a location are skipped.
+func (rss *Rss) ItemsNear(point GeoPoint, km float64) []Item {
+	return rss.filterItems(func(location GeoPoint) bool {
+		return Distance(point, location) <= km
+	})
+}
+
+// Returns the items with a location that matches.
+func (rss *Rss) filterItems(match func(GeoPoint) bool) []Item {
+	items := []Item{}
+	for _, item := range rss.Items {
+		if location, ok := item.Location(); ok && match(location) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Verifies the geographic elements.
+func (v *verifier) verifyGeo(path string, g *GeoLocation) {
+	if g.Point != "" {
+		if points, err := ParseGeoPoints(g.Point); err != nil {
+			v.add(path+".Point", CodeGeo, fmt.Sprintf("Bad georss point (%v)", err))
+		} else if len(points) != 1 {
+			v.add(path+".Point", CodeGeo, "The georss point must have a single latitude and longitude.")
+		}
+	}
+
+	if g.Line != "" {
+		if points, err := ParseGeoPoints(g.Line); err != nil {
+			v.add(path+".Line", CodeGeo, fmt.Sprintf("Bad georss line (%v)", err))
+		} else if len(points) < 2 {
+			v.add(path+".Line", CodeGeo, "The georss line must have at least two points.")
+		}
+	}
+
+	if g.Box != "" {
+		if _, err := ParseGeoBox(g.Box); err != nil {
+			v.add(path+".Box", CodeGeo, fmt.Sprintf("Bad georss box (%v)", err))
+		}
+	}
+
+	if g.Lat != "" || g.Long != "" {
+		if _, err := parseGeoPoint(g.Lat, g.Long); err != nil {
+			v.add(path+".Lat", CodeGeo, fmt.Sprintf("Bad geo:lat and geo:long (%v)", err))
+		}
+	}
+}
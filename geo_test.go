@@ -0,0 +1,145 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"encoding/xml"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGeo(t *testing.T) {
+
+	doc := `<rss version="2.0" xmlns:georss="http://www.georss.org/georss" xmlns:geo="http://www.w3.org/2003/01/geo/wgs84_pos#"><channel>
+<title>title</title><link>http://github.com/efarrer/rssgo/</link><description>Incidents</description>
+<georss:box>45 -123 46 -122</georss:box>
+<item><title>Portland</title><georss:point>45.5152 -122.6784</georss:point></item>
+<item><title>Seattle</title><geo:lat>47.6062</geo:lat><geo:long>-122.3321</geo:long></item>
+<item><title>I-5</title><georss:line>45.5 -122.7 47.6 -122.3</georss:line></item>
+<item><title>Salem</title><georss:box>44.9 -123.1 45.0 -123.0</georss:box></item>
+<item><title>Nowhere</title></item>
+<item><title>Tacoma</title><geo:Point><geo:lat>47.2529</geo:lat><geo:long>-122.4443</geo:long></geo:Point></item>
+</channel></rss>`
+
+	rss, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing the document\n", err)
+	}
+	if rss.Geo == nil || rss.Geo.Box != "45 -123 46 -122" || len(rss.Extensions) != 0 {
+		t.Fatalf("Unexpected channel geo %#v\n", rss.Geo)
+	}
+	if rss.Items[0].Geo.Point != "45.5152 -122.6784" || rss.Items[1].Geo.Lat != "47.6062" ||
+		rss.Items[1].Geo.Long != "-122.3321" || rss.Items[2].Geo.Line == "" || rss.Items[4].Geo != nil {
+		t.Fatalf("Unexpected items %#v\n", rss.Items)
+	}
+	if rss.Items[5].Geo == nil || rss.Items[5].Geo.Lat != "47.2529" || rss.Items[5].Geo.Long != "-122.4443" ||
+		len(rss.Items[5].Extensions) != 0 {
+		t.Fatalf("Unexpected geo:Point item %#v\n", rss.Items[5])
+	}
+	if err := Verify(rss); err != nil {
+		t.Fatalf("Unexpected verify error %v\n", err)
+	}
+
+	for i, expected := range []GeoPoint{{45.5152, -122.6784}, {47.6062, -122.3321}, {45.5, -122.7}, {44.95, -123.05}} {
+		location, ok := rss.Items[i].Location()
+		if !ok || math.Abs(location.Lat-expected.Lat) > 1e-9 || math.Abs(location.Long-expected.Long) > 1e-9 {
+			t.Fatalf("Unexpected location of item %v %v\n", i, location)
+		}
+	}
+	if _, ok := rss.Items[4].Location(); ok {
+		t.Fatalf("Unexpected location for an item without coordinates\n")
+	}
+
+	box, err := ParseGeoBox(rss.Geo.Box)
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing the box\n", err)
+	}
+	items := rss.ItemsInBox(box)
+	if len(items) != 2 || items[0].Title != "Portland" || items[1].Title != "I-5" {
+		t.Fatalf("Unexpected items in the box %v\n", items)
+	}
+
+	portland := GeoPoint{45.5152, -122.6784}
+	if d := Distance(portland, GeoPoint{47.6062, -122.3321}); d < 230 || d > 236 {
+		t.Fatalf("Unexpected distance %v\n", d)
+	}
+	items = rss.ItemsNear(portland, 100)
+	if len(items) != 3 || items[0].Title != "Portland" || items[1].Title != "I-5" || items[2].Title != "Salem" {
+		t.Fatalf("Unexpected nearby items %v\n", items)
+	}
+	seattle := GeoPoint{47.6062, -122.3321}
+	items = rss.ItemsNear(seattle, 50)
+	if len(items) != 2 || items[0].Title != "Seattle" || items[1].Title != "Tacoma" {
+		t.Fatalf("Unexpected items near Seattle %v\n", items)
+	}
+
+	// A box that crosses the 180th meridian
+	fiji := GeoBox{GeoPoint{-21, 177}, GeoPoint{-12, -178}}
+	if !fiji.Contains(GeoPoint{-17, 179}) || !fiji.Contains(GeoPoint{-17, -179}) || fiji.Contains(GeoPoint{-17, 0}) {
+		t.Fatalf("Unexpected Contains results for %v\n", fiji)
+	}
+	if center := fiji.Center(); center.Lat != -16.5 || center.Long != 179.5 {
+		t.Fatalf("Unexpected center %v\n", center)
+	}
+
+	data, err := xml.Marshal(rss)
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) marshalling the document\n", err)
+	}
+	for _, text := range []string{
+		`xmlns:georss="http://www.georss.org/georss"`,
+		`xmlns:geo="http://www.w3.org/2003/01/geo/wgs84_pos#"`,
+		`<description>Incidents</description><georss:box>45 -123 46 -122</georss:box>`,
+		`<georss:point>45.5152 -122.6784</georss:point>`,
+		`<geo:lat>47.6062</geo:lat><geo:long>-122.3321</geo:long>`,
+		`<georss:line>45.5 -122.7 47.6 -122.3</georss:line>`,
+	} {
+		if !strings.Contains(string(data), text) {
+			t.Fatalf("Marshalled document is missing %v: %v\n", text, string(data))
+		}
+	}
+
+	// Violations
+	rss.Geo.Box = "46 -123 45 -122"
+	rss.Items = []Item{
+		{Title: "one", Geo: &GeoLocation{Point: "91 0", Line: "45 -122"}},
+		{Title: "two", Geo: &GeoLocation{Point: "45 -122 46 -123", Lat: "45"}},
+		{Title: "three", Geo: &GeoLocation{Line: "45 -122 46", Box: "45 -122", Long: "east"}},
+		{Title: "four", Geo: &GeoLocation{Point: "NaN NaN", Lat: "Inf", Long: "0"}},
+	}
+
+	expected := ValidationErrors{
+		{Field: "Geo.Box", Code: CodeGeo},
+		{Field: "Items[0].Geo.Point", Code: CodeGeo},
+		{Field: "Items[0].Geo.Line", Code: CodeGeo},
+		{Field: "Items[1].Geo.Point", Code: CodeGeo},
+		{Field: "Items[1].Geo.Lat", Code: CodeGeo},
+		{Field: "Items[2].Geo.Line", Code: CodeGeo},
+		{Field: "Items[2].Geo.Box", Code: CodeGeo},
+		{Field: "Items[2].Geo.Lat", Code: CodeGeo},
+		{Field: "Items[3].Geo.Point", Code: CodeGeo},
+		{Field: "Items[3].Geo.Lat", Code: CodeGeo},
+	}
+	errs := VerifyAll(rss)
+	if len(errs) != len(expected) {
+		t.Fatalf("VerifyAll returned %v errors expected %v: %v\n", len(errs), len(expected), errs)
+	}
+	for i := 0; i != len(expected); i++ {
+		if errs[i].Field != expected[i].Field || errs[i].Code != expected[i].Code {
+			t.Fatalf("VerifyAll returned unexpected error expected: %v/%v got: %v/%v\n",
+				expected[i].Field, expected[i].Code, errs[i].Field, errs[i].Code)
+		}
+	}
+
+	for _, text := range []string{"NaN NaN", "Inf 0", "0 -Inf", "45 NaN"} {
+		if _, err := ParseGeoPoints(text); err == nil {
+			t.Fatalf("Expected an error parsing the points %#v\n", text)
+		}
+	}
+	if _, ok := rss.Items[3].Location(); ok {
+		t.Fatalf("Unexpected location for NaN coordinates\n")
+	}
+}
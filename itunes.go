@@ -0,0 +1,295 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The namespace of Apple's podcast elements, see
+// https://help.apple.com/itc/podcasts_connect/#/itcb54353390
+const ITunesNamespace = "http://www.itunes.com/dtds/podcast-1.0.dtd"
+
+// The allowable Rss.ITunes.Type values
+const (
+	ITunesEpisodic = "episodic"
+	ITunesSerial   = "serial"
+)
+
+// The allowable Item.ITunes.EpisodeType values
+const (
+	ITunesFull    = "full"
+	ITunesTrailer = "trailer"
+	ITunesBonus   = "bonus"
+)
+
+// The iTunes podcast elements of a channel
+type ITunesChannel struct {
+	// Required. The artwork for the podcast
+	Image *ITunesImage `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+
+	// Required. The podcast's categories
+	Categories []ITunesCategory `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd category"`
+
+	// Required. Whether the podcast contains explicit content, "true" or
+	// "false". The older "yes", "no" and "clean" values are also allowed
+	Explicit string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd explicit,omitempty"`
+
+	// Optional. The group responsible for the podcast
+	Author string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd author,omitempty"`
+
+	// Optional. Either rssgo.ITunesEpisodic or rssgo.ITunesSerial
+	Type string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd type,omitempty"`
+
+	// Optional. "Yes" to hide the podcast from Apple Podcasts
+	Block string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd block,omitempty"`
+}
+
+// The iTunes podcast elements of an item
+type ITunesItem struct {
+	// Optional. The artwork for the episode
+	Image *ITunesImage `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+
+	// Optional. Whether the episode contains explicit content, see
+	// ITunesChannel.Explicit
+	Explicit string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd explicit,omitempty"`
+
+	// Optional. The episode's author when it differs from the podcast's
+	Author string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd author,omitempty"`
+
+	// Optional. One of rssgo.ITunesFull, rssgo.ITunesTrailer or
+	// rssgo.ITunesBonus
+	EpisodeType string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd episodeType,omitempty"`
+
+	// Optional. The episode number's text, see ITunesItem.EpisodeNumber
+	Episode string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd episode,omitempty"`
+
+	// Optional. The season number's text, see ITunesItem.SeasonNumber
+	Season string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd season,omitempty"`
+
+	// Optional. The episode's length as seconds, MM:SS or HH:MM:SS. See
+	// rssgo.ParseITunesDuration
+	Duration string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration,omitempty"`
+
+	// Optional. "Yes" to hide the episode from Apple Podcasts
+	Block string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd block,omitempty"`
+}
+
+// Podcast or episode artwork
+type ITunesImage struct {
+	// Required. The URL of a JPEG or PNG image from 1400x1400 to 3000x3000
+	// pixels
+	Href string `xml:"href,attr"`
+}
+
+// An Apple Podcasts category. See rssgo.ITunesCategories()
+type ITunesCategory struct {
+	// Required. The category
+	Text string `xml:"text,attr"`
+
+	// Optional. The subcategories
+	Categories []ITunesCategory `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd category"`
+}
+
+// The Apple Podcasts categories and their subcategories
+var iTunesCategories = map[string][]string{
+	"Arts": {"Books", "Design", "Fashion & Beauty", "Food", "Performing Arts",
+		"Visual Arts"},
+	"Business": {"Careers", "Entrepreneurship", "Investing", "Management",
+		"Marketing", "Non-Profit"},
+	"Comedy":     {"Comedy Interviews", "Improv", "Stand-Up"},
+	"Education":  {"Courses", "How To", "Language Learning", "Self-Improvement"},
+	"Fiction":    {"Comedy Fiction", "Drama", "Science Fiction"},
+	"Government": {},
+	"History":    {},
+	"Health & Fitness": {"Alternative Health", "Fitness", "Medicine",
+		"Mental Health", "Nutrition", "Sexuality"},
+	"Kids & Family": {"Education for Kids", "Parenting", "Pets & Animals",
+		"Stories for Kids"},
+	"Leisure": {"Animation & Manga", "Automotive", "Aviation", "Crafts", "Games",
+		"Hobbies", "Home & Garden", "Video Games"},
+	"Music": {"Music Commentary", "Music History", "Music Interviews"},
+	"News": {"Business News", "Daily News", "Entertainment News",
+		"News Commentary", "Politics", "Sports News", "Tech News"},
+	"Religion & Spirituality": {"Buddhism", "Christianity", "Hinduism", "Islam",
+		"Judaism", "Religion", "Spirituality"},
+	"Science": {"Astronomy", "Chemistry", "Earth Sciences", "Life Sciences",
+		"Mathematics", "Natural Sciences", "Nature", "Physics", "Social Sciences"},
+	"Society & Culture": {"Documentary", "Personal Journals", "Philosophy",
+		"Places & Travel", "Relationships"},
+	"Sports": {"Baseball", "Basketball", "Cricket", "Fantasy Sports", "Football",
+		"Golf", "Hockey", "Rugby", "Running", "Soccer", "Swimming", "Tennis",
+		"Volleyball", "Wilderness", "Wrestling"},
+	"Technology": {},
+	"True Crime": {},
+	"TV & Film": {"After Shows", "Film History", "Film Interviews",
+		"Film Reviews", "TV Reviews"},
+}
+
+// Returns the Apple Podcasts categories and their subcategories.
+func ITunesCategories() map[string][]string {
+	categories := make(map[string][]string, len(iTunesCategories))
+	for category, subcategories := range iTunesCategories {
+		categories[category] = append([]string{}, subcategories...)
+	}
+	return categories
+}
+
+// Parses an itunes:duration value, which is a number of seconds or a time of
+// MM:SS or HH:MM:SS (the hours and the first field may be one digit).
+func ParseITunesDuration(duration string) (time.Duration, error) {
+	fields := strings.Split(strings.TrimSpace(duration), ":")
+	if len(fields) > 3 {
+		return 0, errors.New(fmt.Sprintf("Bad duration %q. Expecting seconds, MM:SS or HH:MM:SS", duration))
+	}
+
+	seconds := 0
+	for i, field := range fields {
+		value, err := strconv.Atoi(field)
+		if err != nil || value < 0 || field[0] == '+' ||
+			(i != 0 && (len(field) != 2 || value > 59)) {
+			return 0, errors.New(fmt.Sprintf("Bad duration %q. Expecting seconds, MM:SS or HH:MM:SS", duration))
+		}
+		seconds = seconds*60 + value
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// Writes the elements without an enclosing element.
+func (c ITunesChannel) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type plain ITunesChannel
+	return marshalFlat(e, plain(c))
+}
+
+// Writes the elements without an enclosing element.
+func (i ITunesItem) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type plain ITunesItem
+	return marshalFlat(e, plain(i))
+}
+
+// Verifies the channel's iTunes elements against Apple's requirements.
+func (v *verifier) verifyITunesChannel(path string, c *ITunesChannel) {
+	if c.Image == nil {
+		v.add(path+".Image", CodeRequired, "The iTunes image must be set.")
+	} else {
+		v.verifyITunesImage(path+".Image", c.Image)
+	}
+
+	if len(c.Categories) == 0 {
+		v.add(path+".Categories", CodeRequired, "At least one iTunes category must be set.")
+	}
+	for i := 0; i != len(c.Categories); i++ {
+		field := fmt.Sprintf("%v.Categories[%v]", path, i)
+		subcategories, ok := iTunesCategories[c.Categories[i].Text]
+		if !ok {
+			v.add(field+".Text", CodeITunesCategory, fmt.Sprintf("Invalid iTunes category %q.", c.Categories[i].Text))
+			continue
+		}
+		for j, sub := range c.Categories[i].Categories {
+			if !containsString(subcategories, sub.Text) || len(sub.Categories) != 0 {
+				v.add(fmt.Sprintf("%v.Categories[%v].Text", field, j), CodeITunesCategory,
+					fmt.Sprintf("Invalid iTunes subcategory %q of %q.", sub.Text, c.Categories[i].Text))
+			}
+		}
+	}
+
+	if c.Explicit == "" {
+		v.add(path+".Explicit", CodeRequired, "The iTunes explicit value must be set.")
+	} else {
+		v.verifyITunesExplicit(path+".Explicit", c.Explicit)
+	}
+
+	if c.Type != "" && c.Type != ITunesEpisodic && c.Type != ITunesSerial {
+		v.add(path+".Type", CodeITunesType, fmt.Sprintf("The iTunes type must be %v or %v.",
+			ITunesEpisodic, ITunesSerial))
+	}
+}
+
+// Returns the episode number and true, or false if there's no episode number
+// or it isn't an integer.
+func (i *ITunesItem) EpisodeNumber() (int, bool) {
+	return parseCount(i.Episode)
+}
+
+// Returns the season number and true, or false if there's no season number or
+// it isn't an integer.
+func (i *ITunesItem) SeasonNumber() (int, bool) {
+	return parseCount(i.Season)
+}
+
+// Parses the text of an element that holds a number, such as <itunes:episode>.
+func parseCount(text string) (int, bool) {
+	n, err := strconv.Atoi(strings.TrimSpace(text))
+	return n, err == nil
+}
+
+// Verifies the item's iTunes elements against Apple's requirements.
+func (v *verifier) verifyITunesItem(path string, i *ITunesItem) {
+	if i.Image != nil {
+		v.verifyITunesImage(path+".Image", i.Image)
+	}
+
+	if i.Explicit != "" {
+		v.verifyITunesExplicit(path+".Explicit", i.Explicit)
+	}
+
+	if i.EpisodeType != "" && i.EpisodeType != ITunesFull &&
+		i.EpisodeType != ITunesTrailer && i.EpisodeType != ITunesBonus {
+		v.add(path+".EpisodeType", CodeITunesType, fmt.Sprintf("The iTunes episode type must be %v, %v or %v.",
+			ITunesFull, ITunesTrailer, ITunesBonus))
+	}
+
+	if episode, ok := i.EpisodeNumber(); i.Episode != "" && (!ok || episode < 1) {
+		v.add(path+".Episode", CodeRange, "The iTunes episode must be a positive integer.")
+	}
+
+	if season, ok := i.SeasonNumber(); i.Season != "" && (!ok || season < 1) {
+		v.add(path+".Season", CodeRange, "The iTunes season must be a positive integer.")
+	}
+
+	if i.Duration != "" {
+		if _, err := ParseITunesDuration(i.Duration); err != nil {
+			v.add(path+".Duration", CodeITunesDuration, err.Error())
+		}
+	}
+}
+
+// Verifies that the image is a JPEG or PNG URL.
+func (v *verifier) verifyITunesImage(path string, image *ITunesImage) {
+	v.verifyURL(path+".Href", "iTunes image href", image.Href)
+
+	href := strings.ToLower(image.Href)
+	if i := strings.IndexAny(href, "?#"); i != -1 {
+		href = href[:i]
+	}
+	if image.Href != "" && !strings.HasSuffix(href, ".jpg") &&
+		!strings.HasSuffix(href, ".jpeg") && !strings.HasSuffix(href, ".png") {
+		v.add(path+".Href", CodeITunesImage, "The iTunes image must be a .jpg, .jpeg or .png file.")
+	}
+}
+
+// Verifies an explicit value.
+func (v *verifier) verifyITunesExplicit(path string, explicit string) {
+	switch explicit {
+	case "true", "false", "yes", "no", "clean":
+		return
+	}
+	v.add(path, CodeITunesExplicit, "The iTunes explicit value must be true or false.")
+}
+
+// Returns true if the value is in values.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestITunes(t *testing.T) {
+
+	doc := `<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd"><channel>
+<title>title</title><link>http://github.com/efarrer/rssgo/</link><description>A podcast</description>
+<itunes:author>The author</itunes:author>
+<itunes:image href="http://example.com/art.jpg"/>
+<itunes:category text="Technology"/>
+<itunes:category text="Arts"><itunes:category text="Design"/></itunes:category>
+<itunes:explicit>false</itunes:explicit>
+<itunes:type>serial</itunes:type>
+<itunes:new-feed-url>http://example.com/new</itunes:new-feed-url>
+<item><title>one</title><author>author@example.com</author>
+<itunes:author>The guest</itunes:author>
+<itunes:episodeType>full</itunes:episodeType>
+<itunes:episode>3</itunes:episode><itunes:season>2</itunes:season>
+<itunes:duration>1:02:03</itunes:duration><itunes:block>Yes</itunes:block>
+</item>
+<item><title>two</title></item>
+</channel></rss>`
+
+	rss, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing the document\n", err)
+	}
+	c := rss.ITunes
+	if c == nil || c.Author != "The author" || c.Image.Href != "http://example.com/art.jpg" ||
+		c.Explicit != "false" || c.Type != ITunesSerial || len(c.Categories) != 2 ||
+		c.Categories[1].Text != "Arts" || c.Categories[1].Categories[0].Text != "Design" {
+		t.Fatalf("Unexpected channel iTunes %#v\n", c)
+	}
+	if len(rss.Extensions) != 1 || rss.Extensions[0].XMLName.Local != "new-feed-url" {
+		t.Fatalf("Unexpected extensions %#v\n", rss.Extensions)
+	}
+	i := rss.Items[0].ITunes
+	if rss.Items[0].Author != "author@example.com" || i == nil || i.Author != "The guest" ||
+		i.EpisodeType != ITunesFull || i.Episode != "3" || i.Season != "2" ||
+		i.Duration != "1:02:03" || i.Block != "Yes" {
+		t.Fatalf("Unexpected item iTunes %#v\n", rss.Items[0])
+	}
+	if rss.Items[1].ITunes != nil {
+		t.Fatalf("Items without iTunes elements should have a nil ITunes %#v\n", rss.Items[1].ITunes)
+	}
+	if err := Verify(rss); err != nil {
+		t.Fatalf("Unexpected verify error %v\n", err)
+	}
+
+	data, err := xml.Marshal(rss)
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) marshalling the document\n", err)
+	}
+	for _, text := range []string{
+		`<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">`,
+		`<itunes:image href="http://example.com/art.jpg"></itunes:image>`,
+		`<itunes:category text="Arts"><itunes:category text="Design"></itunes:category></itunes:category>`,
+		`<itunes:explicit>false</itunes:explicit><itunes:author>The author</itunes:author><itunes:type>serial</itunes:type><itunes:new-feed-url>`,
+		`<author>author@example.com</author>`,
+		`<itunes:author>The guest</itunes:author><itunes:episodeType>full</itunes:episodeType><itunes:episode>3</itunes:episode><itunes:season>2</itunes:season><itunes:duration>1:02:03</itunes:duration><itunes:block>Yes</itunes:block></item>`,
+		`<item><title>two</title></item>`,
+	} {
+		if !strings.Contains(string(data), text) {
+			t.Fatalf("Marshalled document is missing %v: %v\n", text, string(data))
+		}
+	}
+
+	// Violations
+	rss.ITunes = &ITunesChannel{
+		Image:      &ITunesImage{Href: "http://example.com/art.gif"},
+		Categories: []ITunesCategory{{Text: "Cats"}, {Text: "Arts", Categories: []ITunesCategory{{Text: "Golf"}}}},
+		Explicit:   "maybe",
+		Type:       "daily"}
+	rss.Items[0].ITunes = &ITunesItem{
+		Image:       &ITunesImage{Href: "art.png"},
+		EpisodeType: "extra",
+		Episode:     "0",
+		Season:      "two",
+		Duration:    "1:2:3"}
+	rss.Items[1].ITunes = &ITunesItem{Explicit: "clean", Duration: "3600"}
+
+	expected := ValidationErrors{
+		{Field: "ITunes.Image.Href", Code: CodeITunesImage},
+		{Field: "ITunes.Categories[0].Text", Code: CodeITunesCategory},
+		{Field: "ITunes.Categories[1].Categories[0].Text", Code: CodeITunesCategory},
+		{Field: "ITunes.Explicit", Code: CodeITunesExplicit},
+		{Field: "ITunes.Type", Code: CodeITunesType},
+		{Field: "Items[0].ITunes.Image.Href", Code: CodeURLNotAbsolute},
+		{Field: "Items[0].ITunes.EpisodeType", Code: CodeITunesType},
+		{Field: "Items[0].ITunes.Episode", Code: CodeRange},
+		{Field: "Items[0].ITunes.Season", Code: CodeRange},
+		{Field: "Items[0].ITunes.Duration", Code: CodeITunesDuration},
+	}
+	errs := VerifyAll(rss)
+	if len(errs) != len(expected) {
+		t.Fatalf("VerifyAll returned %v errors expected %v: %v\n", len(errs), len(expected), errs)
+	}
+	for i := 0; i != len(expected); i++ {
+		if errs[i].Field != expected[i].Field || errs[i].Code != expected[i].Code {
+			t.Fatalf("VerifyAll returned unexpected error expected: %v/%v got: %v/%v\n",
+				expected[i].Field, expected[i].Code, errs[i].Field, errs[i].Code)
+		}
+	}
+
+	rss.ITunes = &ITunesChannel{}
+	errs = VerifyAll(rss)
+	if len(errs) < 3 || errs[0].Field != "ITunes.Image" || errs[1].Field != "ITunes.Categories" ||
+		errs[2].Field != "ITunes.Explicit" || errs[2].Code != CodeRequired {
+		t.Fatalf("Missing iTunes elements weren't reported %v\n", errs)
+	}
+
+	// Changing the returned categories doesn't change what's allowed
+	categories := ITunesCategories()
+	categories["Podcasts"] = nil
+	categories["Arts"][0] = "Podcasts"
+	rss.ITunes.Categories = []ITunesCategory{{Text: "Podcasts"}, {Text: "Arts", Categories: []ITunesCategory{{Text: "Podcasts"}}}}
+	errs = VerifyAll(rss)
+	if len(errs) < 3 || errs[1].Field != "ITunes.Categories[0].Text" ||
+		errs[2].Field != "ITunes.Categories[1].Categories[0].Text" || ITunesCategories()["Arts"][0] != "Books" {
+		t.Fatalf("Expected ITunesCategories to return a copy %v\n", errs)
+	}
+}
+
+func TestITunesNumbers(t *testing.T) {
+
+	rss, err := Parse(strings.NewReader(`<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+<channel><title>title</title><link>http://example.com/</link><description>d</description>
+<item><title>one</title><itunes:episode> 3 </itunes:episode><itunes:season>pilot</itunes:season></item>
+</channel></rss>`))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing a non-numeric season\n", err)
+	}
+	i := rss.Items[0].ITunes
+	if episode, ok := i.EpisodeNumber(); !ok || episode != 3 {
+		t.Fatalf("Unexpected episode number %v %v\n", episode, ok)
+	}
+	if _, ok := i.SeasonNumber(); ok || i.Season != "pilot" {
+		t.Fatalf("Unexpected season %#v\n", i)
+	}
+	if _, ok := (&ITunesItem{}).EpisodeNumber(); ok {
+		t.Fatalf("Unexpected episode number for a missing episode\n")
+	}
+}
+
+func TestParseITunesDuration(t *testing.T) {
+
+	for duration, expected := range map[string]time.Duration{
+		"0":        0,
+		"3723":     3723 * time.Second,
+		"62:03":    62*time.Minute + 3*time.Second,
+		"1:02:03":  time.Hour + 2*time.Minute + 3*time.Second,
+		"01:02:03": time.Hour + 2*time.Minute + 3*time.Second,
+		" 5:00 ":   5 * time.Minute,
+	} {
+		d, err := ParseITunesDuration(duration)
+		if err != nil || d != expected {
+			t.Fatalf("Unexpected duration for %q expected %v got %v (%v)\n", duration, expected, d, err)
+		}
+	}
+
+	for _, duration := range []string{"", "abc", "-5", "+5", "1:2", "1:60", "1:02:03:04", "1::03", "1.5"} {
+		if _, err := ParseITunesDuration(duration); err == nil {
+			t.Fatalf("Expected an error for %q\n", duration)
+		}
+	}
+}
@@ -0,0 +1,634 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The version of the JSON Feeds written by rssgo, see
+// https://www.jsonfeed.org/version/1.1/
+const JSONFeedVersion = "https://jsonfeed.org/version/1.1"
+
+// The version of JSON Feed 1.0 documents, which are read but never written
+const JSONFeedVersion10 = "https://jsonfeed.org/version/1"
+
+// The hub type of WebSub hubs, which are the rssgo.AtomRelHub links
+const JSONFeedWebSub = "WebSub"
+
+// A JSON Feed 1.1 document. JSON Feed 1.0 documents are read too
+type JSONFeed struct {
+	// Required. The JSON Feed version URL, rssgo.JSONFeedVersion
+	Version string `json:"version"`
+
+	// Required. The name of the feed
+	Title string `json:"title"`
+
+	// Optional. The URL of the web site the feed describes
+	HomePageUrl string `json:"home_page_url,omitempty"`
+
+	// Optional. The URL of the feed itself
+	FeedUrl string `json:"feed_url,omitempty"`
+
+	// Optional. A description of the feed
+	Description string `json:"description,omitempty"`
+
+	// Optional. A note for people who look at the feed's JSON
+	UserComment string `json:"user_comment,omitempty"`
+
+	// Optional. The URL of the next page of a paginated feed
+	NextUrl string `json:"next_url,omitempty"`
+
+	// Optional. The URL of a large square image for the feed
+	Icon string `json:"icon,omitempty"`
+
+	// Optional. The URL of a small square image for the feed
+	Favicon string `json:"favicon,omitempty"`
+
+	// Optional. The authors of the feed
+	Authors []JSONFeedAuthor `json:"authors,omitempty"`
+
+	// Optional. The JSON Feed 1.0 author, read but never written
+	Author *JSONFeedAuthor `json:"author,omitempty"`
+
+	// Optional. The language of the feed, as in Rss.Language
+	Language string `json:"language,omitempty"`
+
+	// Optional. True if the feed will never be updated again
+	Expired bool `json:"expired,omitempty"`
+
+	// Optional. The endpoints that can be used to subscribe to updates
+	Hubs []JSONFeedHub `json:"hubs,omitempty"`
+
+	// Required. The items of the feed
+	Items []JSONFeedItem `json:"items"`
+}
+
+// An item of a JSON Feed
+type JSONFeedItem struct {
+	// Required. The item's unique identifier
+	Id JSONFeedId `json:"id"`
+
+	// Optional. The URL of the item's page
+	Url string `json:"url,omitempty"`
+
+	// Optional. The URL of a page the item is about
+	ExternalUrl string `json:"external_url,omitempty"`
+
+	// Optional. The title of the item
+	Title string `json:"title,omitempty"`
+
+	// Either ContentHtml or ContentText is required. The item's HTML
+	ContentHtml string `json:"content_html,omitempty"`
+
+	// Either ContentHtml or ContentText is required. The item's plain text
+	ContentText string `json:"content_text,omitempty"`
+
+	// Optional. A plain text summary of the item
+	Summary string `json:"summary,omitempty"`
+
+	// Optional. The URL of the item's main image
+	Image string `json:"image,omitempty"`
+
+	// Optional. The URL of an image to use as a banner
+	BannerImage string `json:"banner_image,omitempty"`
+
+	// Optional. The RFC 3339 date the item was published
+	DatePublished string `json:"date_published,omitempty"`
+
+	// Optional. The RFC 3339 date the item was modified
+	DateModified string `json:"date_modified,omitempty"`
+
+	// Optional. The authors of the item, defaults to the feed's authors
+	Authors []JSONFeedAuthor `json:"authors,omitempty"`
+
+	// Optional. The JSON Feed 1.0 author, read but never written
+	Author *JSONFeedAuthor `json:"author,omitempty"`
+
+	// Optional. The item's tags
+	Tags []string `json:"tags,omitempty"`
+
+	// Optional. The language of the item, defaults to the feed's language
+	Language string `json:"language,omitempty"`
+
+	// Optional. Related resources, such as podcast audio files
+	Attachments []JSONFeedAttachment `json:"attachments,omitempty"`
+}
+
+// A JSON Feed item id. Ids should be strings, numbers are read as strings
+type JSONFeedId string
+
+// Reads an id that's a string or a number.
+func (id *JSONFeedId) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*id = JSONFeedId(text)
+		return nil
+	}
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return errors.New(fmt.Sprintf("Expecting a string or number id but found %v", string(data)))
+	}
+	*id = JSONFeedId(number)
+	return nil
+}
+
+// Returns the id as a string.
+func (id JSONFeedId) String() string {
+	return string(id)
+}
+
+// A JSON Feed author. At least one of the fields must be set
+type JSONFeedAuthor struct {
+	// Optional. The author's name
+	Name string `json:"name,omitempty"`
+
+	// Optional. The URL of the author's site, or a mailto: URL
+	Url string `json:"url,omitempty"`
+
+	// Optional. The URL of the author's picture
+	Avatar string `json:"avatar,omitempty"`
+}
+
+// An endpoint that can be used to subscribe to a JSON Feed's updates
+type JSONFeedHub struct {
+	// Required. The hub's protocol, for example rssgo.JSONFeedWebSub
+	Type string `json:"type"`
+
+	// Required. The URL of the hub
+	Url string `json:"url"`
+}
+
+// A resource related to a JSON Feed item
+type JSONFeedAttachment struct {
+	// Required. The URL of the resource
+	Url string `json:"url"`
+
+	// Required. The resource's MIME type
+	MimeType string `json:"mime_type"`
+
+	// Optional. A name for the resource
+	Title string `json:"title,omitempty"`
+
+	// Optional. The size in bytes
+	SizeInBytes int64 `json:"size_in_bytes,omitempty"`
+
+	// Optional. The length in seconds
+	DurationInSeconds float64 `json:"duration_in_seconds,omitempty"`
+}
+
+// Parses a JSON Feed document into a Rss object. Values that can't be
+// converted, such as unparseable dates, are dropped and listed in the report,
+// see rssgo.ParseJSONFeedWithOptions to reject invalid feeds. The JSON Feed
+// fields are mapped as follows:
+//
+//	title, description, language    Title, Description, Language
+//	home_page_url                   Link
+//	feed_url, next_url, hubs        AtomLinks with the self, next and hub rels
+//	icon, favicon                   Image and Atom.Icon
+//	authors                         DC.Creators
+//	item id, url, title             Guid, Link, Title
+//	item content_html, summary      Content, Description
+//	item date_published             PubDate
+//	item date_modified              Atom.Updated
+//	item authors                    DC.Creators
+//	item tags                       Categories
+//	item attachments                Enclosure for the first, Media.Contents
+//	                                for the others
+//	item image                      Media.Thumbnails
+//
+// The report lists the values that weren't converted exactly.
+func ParseJSONFeed(r io.Reader) (*Rss, ConversionReport, error) {
+	return ParseJSONFeedWithOptions(r, ParseOptions{})
+}
+
+// Parses a JSON Feed document into a Rss object using the provided options.
+// With ParseOptions.Verify the JSON Feed is checked with rssgo.VerifyJSONFeed
+// before it's converted, its rssgo.ValidationErrors are returned if it's
+// invalid.
+func ParseJSONFeedWithOptions(r io.Reader, opts ParseOptions) (*Rss, ConversionReport, error) {
+	feed := &JSONFeed{}
+	if err := json.NewDecoder(r).Decode(feed); err != nil {
+		return nil, nil, errors.New(fmt.Sprintf("Unable to parse the JSON Feed document (%v)", err))
+	}
+	if opts.Verify {
+		if errs := VerifyJSONFeed(feed); errs != nil {
+			return nil, nil, errs
+		}
+	}
+	rss, report := feed.Rss()
+	return rss, report, nil
+}
+
+// Converts the JSON Feed to a Rss object, see rssgo.ParseJSONFeed.
+func (feed *JSONFeed) Rss() (*Rss, ConversionReport) {
+	var report ConversionReport
+	rss := &Rss{Version: Version, Title: feed.Title, Link: feed.HomePageUrl, Description: feed.Description,
+		Language: strings.ToLower(feed.Language)}
+
+	if rss.Link == "" && feed.FeedUrl != "" {
+		rss.Link = feed.FeedUrl
+		report.add("Link", ConversionSynthesized, "The feed has no home_page_url, its feed_url was used")
+	}
+	if rss.Description == "" {
+		rss.Description = html.EscapeString(feed.Title)
+		report.add("Description", ConversionSynthesized, "The feed has no description, its title was used")
+	}
+
+	if feed.FeedUrl != "" {
+		rss.AtomLinks = append(rss.AtomLinks, AtomLink{Href: feed.FeedUrl, Rel: AtomRelSelf, Type: "application/feed+json"})
+	}
+	if feed.NextUrl != "" {
+		rss.AtomLinks = append(rss.AtomLinks, AtomLink{Href: feed.NextUrl, Rel: AtomRelNext})
+	}
+	for i, hub := range feed.Hubs {
+		if strings.EqualFold(hub.Type, JSONFeedWebSub) {
+			rss.AtomLinks = append(rss.AtomLinks, AtomLink{Href: hub.Url, Rel: AtomRelHub})
+		} else {
+			report.add(fmt.Sprintf("Hubs[%v]", i), ConversionDropped, fmt.Sprintf("The %v hub was dropped", hub.Type))
+		}
+	}
+
+	if feed.Icon != "" {
+		rss.Image = &Image{Url: feed.Icon, Title: rss.Title, Link: rss.Link}
+	}
+	if feed.Favicon != "" {
+		rss.Atom = &AtomChannel{Icon: feed.Favicon}
+		report.add("Atom.Icon", ConversionKept, "The favicon was kept")
+	}
+	rss.DC = jsonFeedCreators("DC.Creators", jsonFeedAuthors(feed.Authors, feed.Author), &report)
+
+	if feed.UserComment != "" {
+		report.add("UserComment", ConversionDropped, "The user_comment was dropped")
+	}
+	if feed.Expired {
+		report.add("Expired", ConversionDropped, "The expired flag was dropped")
+	}
+
+	for i := 0; i != len(feed.Items); i++ {
+		rss.Items = append(rss.Items, feed.Items[i].item(fmt.Sprintf("Items[%v]", i), &report))
+	}
+	return rss, report
+}
+
+// Converts the JSON Feed item to an Item.
+func (j *JSONFeedItem) item(path string, report *ConversionReport) Item {
+	item := Item{Title: j.Title, Link: j.Url, Description: j.Summary, Content: j.ContentHtml}
+	item.Guid = &Guid{Guid: string(j.Id), IsPermaLink: j.Url != "" && string(j.Id) == j.Url}
+
+	if j.ContentHtml == "" {
+		item.Content = html.EscapeString(j.ContentText)
+	} else if j.ContentText != "" {
+		report.add(path+".Content", ConversionDropped, "The content_text was dropped, the content_html was kept")
+	}
+	if item.Title == "" && item.Description == "" {
+		item.Description = item.Content
+		report.add(path+".Description", ConversionSynthesized, "The item has no title or summary, its content was used")
+	}
+
+	if j.DatePublished != "" {
+		if date := W3CTimeFromString(j.DatePublished); !date.Time.IsZero() {
+			item.PubDate = NewRssTime(date.Time)
+		} else {
+			report.add(path+".PubDate", ConversionDropped,
+				fmt.Sprintf("The date_published %#v can't be parsed and was dropped", j.DatePublished))
+		}
+	}
+	if j.DateModified != "" {
+		item.Atom = &AtomItem{Updated: W3CTimeFromString(j.DateModified)}
+		report.add(path+".Atom.Updated", ConversionKept, "The date_modified was kept")
+	}
+	if j.ExternalUrl != "" {
+		item.Atom = itemAtom(&item)
+		item.Atom.Links = append(item.Atom.Links, AtomLink{Href: j.ExternalUrl, Rel: "related"})
+		report.add(path+".Atom.Links", ConversionKept, "The external_url was kept as a related link")
+	}
+
+	item.DC = jsonFeedCreators(path+".DC.Creators", jsonFeedAuthors(j.Authors, j.Author), report)
+	for _, tag := range j.Tags {
+		item.Categories = append(item.Categories, Category{Category: tag})
+	}
+
+	for i, attachment := range j.Attachments {
+		if i == 0 {
+			item.Enclosure = &Enclosure{Url: attachment.Url, Length: attachment.SizeInBytes, Type: attachment.MimeType}
+			if attachment.Title != "" || attachment.DurationInSeconds != 0 {
+				report.add(path+".Enclosure", ConversionDropped, "The first attachment's title and duration were dropped")
+			}
+			continue
+		}
+		if item.Media == nil {
+			item.Media = &MediaItem{}
+		}
+		content := MediaContent{Url: attachment.Url, Type: attachment.MimeType}
+		if attachment.SizeInBytes != 0 {
+			content.FileSize = strconv.FormatInt(attachment.SizeInBytes, 10)
+		}
+		if attachment.DurationInSeconds != 0 {
+			content.Duration = strconv.Itoa(int(attachment.DurationInSeconds + 0.5))
+		}
+		if attachment.Title != "" {
+			content.Title = &MediaText{Text: attachment.Title}
+		}
+		item.Media.Contents = append(item.Media.Contents, content)
+	}
+
+	if j.Image != "" {
+		if item.Media == nil {
+			item.Media = &MediaItem{}
+		}
+		item.Media.Thumbnails = append(item.Media.Thumbnails, MediaThumbnail{Url: j.Image})
+	}
+	if j.BannerImage != "" {
+		report.add(path+".Media", ConversionDropped, "The banner_image was dropped")
+	}
+	if j.Language != "" {
+		report.add(path, ConversionDropped, "The item's language was dropped")
+	}
+	return item
+}
+
+// Returns the authors, or the JSON Feed 1.0 author.
+func jsonFeedAuthors(authors []JSONFeedAuthor, author *JSONFeedAuthor) []JSONFeedAuthor {
+	if len(authors) == 0 && author != nil {
+		return []JSONFeedAuthor{*author}
+	}
+	return authors
+}
+
+// Returns the Dublin Core creators of the authors, or nil if there are none.
+func jsonFeedCreators(field string, authors []JSONFeedAuthor, report *ConversionReport) *DublinCore {
+	var creators []string
+	for _, author := range authors {
+		if author.Name != "" {
+			creators = append(creators, author.Name)
+		}
+		if author.Url != "" || author.Avatar != "" {
+			report.add(field, ConversionDropped, fmt.Sprintf("The url and avatar of the author %v were dropped", author.Name))
+		}
+	}
+	if len(creators) == 0 {
+		return nil
+	}
+	return &DublinCore{Creators: creators}
+}
+
+// Writes the Rss as a JSON Feed 1.1 document. The conversion is the reverse
+// of rssgo.ParseJSONFeed. An item without content has its Description as its
+// content_html, its id is its Guid or Link. The Enclosure and Media.Contents
+// are written as attachments. RSS fields that JSON Feed doesn't have, such as
+// Copyright or the Category domains, are reported as dropped. The JSON Feed is
+// checked with rssgo.VerifyJSONFeed before it's written, its
+// rssgo.ValidationErrors are returned if it's invalid.
+func WriteJSONFeed(w io.Writer, rss *Rss) (ConversionReport, error) {
+	feed, report := NewJSONFeed(rss)
+	if errs := VerifyJSONFeed(feed); errs != nil {
+		return report, errs
+	}
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return report, e.Encode(feed)
+}
+
+// Converts the Rss to a JSON Feed, see rssgo.WriteJSONFeed.
+func NewJSONFeed(rss *Rss) (*JSONFeed, ConversionReport) {
+	var report ConversionReport
+	feed := &JSONFeed{Version: JSONFeedVersion, Title: rss.Title, HomePageUrl: rss.Link,
+		Description: rss.Description, Language: rss.Language, Items: []JSONFeedItem{}}
+
+	for _, link := range rss.AtomLinks {
+		switch link.Rel {
+		case AtomRelSelf:
+			feed.FeedUrl = link.Href
+		case AtomRelNext:
+			feed.NextUrl = link.Href
+		case AtomRelHub:
+			feed.Hubs = append(feed.Hubs, JSONFeedHub{Type: JSONFeedWebSub, Url: link.Href})
+		}
+	}
+	if rss.Image != nil {
+		feed.Icon = rss.Image.Url
+	}
+	if rss.Atom != nil {
+		feed.Favicon = rss.Atom.Icon
+	}
+	feed.Authors = jsonFeedAuthorsOf(rss.ManagingEditor, rss.DC)
+
+	if rss.Copyright != "" {
+		report.add("Copyright", ConversionDropped, "The copyright was dropped")
+	}
+	if len(rss.Categories) != 0 {
+		report.add("Categories", ConversionDropped, "The channel's categories were dropped")
+	}
+
+	for i := 0; i != len(rss.Items); i++ {
+		feed.Items = append(feed.Items, jsonFeedItem(fmt.Sprintf("Items[%v]", i), &rss.Items[i], &report))
+	}
+	return feed, report
+}
+
+// Converts the Item to a JSON Feed item.
+func jsonFeedItem(path string, item *Item, report *ConversionReport) JSONFeedItem {
+	j := JSONFeedItem{Title: item.Title, Url: item.Link, ContentHtml: item.Content, Summary: item.Description}
+	if item.Content == "" {
+		j.ContentHtml, j.Summary = item.Description, ""
+	}
+	if j.ContentHtml == "" {
+		j.ContentText = item.Title
+		report.add(path+".ContentText", ConversionSynthesized, "The item has no content or description, its title was used")
+	}
+
+	switch {
+	case item.Guid != nil && strings.TrimSpace(item.Guid.Guid) != "":
+		j.Id = JSONFeedId(strings.TrimSpace(item.Guid.Guid))
+		if j.Url == "" && item.Guid.IsPermaLink {
+			j.Url = j.Id.String()
+		}
+	default:
+		j.Id = JSONFeedId(item.Link)
+	}
+
+	if !item.EffectivePubDate().IsZero() {
+		j.DatePublished = item.EffectivePubDate().Format(time.RFC3339)
+	} else if !item.PubDate.IsZero() {
+		report.add(path+".PubDate", ConversionDropped, fmt.Sprintf("The date %#v can't be parsed and was dropped", item.PubDate.Text))
+	}
+	if item.Atom != nil && !item.Atom.Updated.Time.IsZero() {
+		j.DateModified = item.Atom.Updated.Time.Format(time.RFC3339)
+	}
+
+	j.Authors = jsonFeedAuthorsOf(item.Author, item.DC)
+	for i, category := range item.Categories {
+		j.Tags = append(j.Tags, category.Category)
+		if category.Domain != "" {
+			report.add(fmt.Sprintf("%v.Categories[%v].Domain", path, i), ConversionDropped, "The category's domain was dropped")
+		}
+	}
+
+	for _, rendition := range item.Renditions() {
+		if rendition.Type == "" {
+			report.add(path+".Media", ConversionDropped, fmt.Sprintf("The media %v has no type and was dropped", rendition.Url))
+			continue
+		}
+		size, _ := rendition.FileSizeValue()
+		duration, _ := rendition.DurationValue()
+		attachment := JSONFeedAttachment{Url: rendition.Url, MimeType: rendition.Type, SizeInBytes: size,
+			DurationInSeconds: float64(duration)}
+		if rendition.Title != nil {
+			attachment.Title = rendition.Title.Text
+		}
+		j.Attachments = append(j.Attachments, attachment)
+	}
+	if item.Media != nil && len(item.Media.Thumbnails) != 0 {
+		j.Image = item.Media.Thumbnails[0].Url
+	}
+
+	if item.Comments != "" {
+		report.add(path+".Comments", ConversionDropped, "The comments URL was dropped")
+	}
+	if item.Source != nil {
+		report.add(path+".Source", ConversionDropped, "The source was dropped")
+	}
+	return j
+}
+
+// Returns the JSON Feed authors of an RSS email address, such as
+// "jane@example.com (Jane Doe)", and the Dublin Core creators. Email addresses
+// are written as mailto: URLs.
+func jsonFeedAuthorsOf(email string, dc *DublinCore) []JSONFeedAuthor {
+	var authors []JSONFeedAuthor
+	for _, person := range atomPersons(email, dc) {
+		author := JSONFeedAuthor{Name: person.Name}
+		if person.Email != "" {
+			author.Url = "mailto:" + person.Email
+			if author.Name == person.Email {
+				author.Name = ""
+			}
+		}
+		authors = append(authors, author)
+	}
+	return authors
+}
+
+// Verifies that the JSON Feed conforms to the JSON Feed 1.1 spec. Every
+// violation is returned, nil is returned if there are none.
+func VerifyJSONFeed(feed *JSONFeed) ValidationErrors {
+	v := &verifier{}
+
+	if feed.Version != JSONFeedVersion && feed.Version != JSONFeedVersion10 {
+		v.add("Version", CodeVersion, fmt.Sprintf("Bad version. Expecting %v", JSONFeedVersion))
+	}
+	if strings.TrimSpace(feed.Title) == "" {
+		v.add("Title", CodeRequired, "Empty title. The title must be set")
+	}
+
+	for _, u := range []struct{ field, name, value string }{
+		{"HomePageUrl", "home_page_url", feed.HomePageUrl},
+		{"FeedUrl", "feed_url", feed.FeedUrl},
+		{"NextUrl", "next_url", feed.NextUrl},
+		{"Icon", "icon", feed.Icon},
+		{"Favicon", "favicon", feed.Favicon},
+	} {
+		if u.value != "" {
+			v.verifyURL(u.field, u.name, u.value)
+		}
+	}
+
+	v.verifyJSONFeedAuthors("", feed.Authors, feed.Author)
+
+	for i, hub := range feed.Hubs {
+		if hub.Type == "" {
+			v.add(fmt.Sprintf("Hubs[%v].Type", i), CodeRequired, "The hub's type must be set.")
+		}
+		v.verifyURL(fmt.Sprintf("Hubs[%v].Url", i), "hub url", hub.Url)
+	}
+
+	ids := map[JSONFeedId]bool{}
+	for i := 0; i != len(feed.Items); i++ {
+		path := fmt.Sprintf("Items[%v]", i)
+		item := &feed.Items[i]
+		if strings.TrimSpace(item.Id.String()) == "" {
+			v.add(path+".Id", CodeRequired, "The item's id must be set.")
+		} else if ids[item.Id] {
+			v.add(path+".Id", CodeDuplicateId, fmt.Sprintf("The item id %v isn't unique.", item.Id))
+		}
+		ids[item.Id] = true
+		v.verifyJSONFeedItem(path, item)
+	}
+	return v.errs
+}
+
+// Verifies a JSON Feed item.
+func (v *verifier) verifyJSONFeedItem(path string, item *JSONFeedItem) {
+	if item.ContentHtml == "" && item.ContentText == "" {
+		v.add(path+".ContentHtml", CodeRequired, "The item's content_html or content_text must be set.")
+	}
+
+	for _, u := range []struct{ field, name, value string }{
+		{"Url", "item url", item.Url},
+		{"ExternalUrl", "item external_url", item.ExternalUrl},
+		{"Image", "item image", item.Image},
+		{"BannerImage", "item banner_image", item.BannerImage},
+	} {
+		if u.value != "" {
+			v.verifyURL(path+"."+u.field, u.name, u.value)
+		}
+	}
+
+	for _, date := range []struct{ field, value string }{
+		{"DatePublished", item.DatePublished},
+		{"DateModified", item.DateModified},
+	} {
+		if date.value == "" {
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339, date.value); err != nil {
+			v.add(path+"."+date.field, CodeDate, fmt.Sprintf("Bad date %#v. Expecting an RFC 3339 date", date.value))
+		}
+	}
+
+	v.verifyJSONFeedAuthors(path+".", item.Authors, item.Author)
+
+	for i, attachment := range item.Attachments {
+		field := fmt.Sprintf("%v.Attachments[%v]", path, i)
+		v.verifyURL(field+".Url", "attachment url", attachment.Url)
+		if attachment.MimeType == "" {
+			v.add(field+".MimeType", CodeRequired, "The attachment's mime_type must be set.")
+		}
+		if attachment.SizeInBytes < 0 {
+			v.add(field+".SizeInBytes", CodeRange, "The attachment's size_in_bytes must not be negative.")
+		}
+		if attachment.DurationInSeconds < 0 {
+			v.add(field+".DurationInSeconds", CodeRange, "The attachment's duration_in_seconds must not be negative.")
+		}
+	}
+}
+
+// Verifies the JSON Feed authors, and the JSON Feed 1.0 author.
+func (v *verifier) verifyJSONFeedAuthors(prefix string, authors []JSONFeedAuthor, author *JSONFeedAuthor) {
+	for i := 0; i != len(authors); i++ {
+		v.verifyJSONFeedAuthor(fmt.Sprintf("%vAuthors[%v]", prefix, i), &authors[i])
+	}
+	if author != nil {
+		v.verifyJSONFeedAuthor(prefix+"Author", author)
+	}
+}
+
+// Verifies a JSON Feed author.
+func (v *verifier) verifyJSONFeedAuthor(field string, author *JSONFeedAuthor) {
+	if author.Name == "" && author.Url == "" && author.Avatar == "" {
+		v.add(field, CodeRequired, "The author's name, url or avatar must be set.")
+	}
+	if author.Avatar != "" {
+		v.verifyURL(field+".Avatar", "author avatar", author.Avatar)
+	}
+}
@@ -0,0 +1,301 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testJSONFeed = `{
+  "version": "https://jsonfeed.org/version/1.1",
+  "title": "Example podcast",
+  "home_page_url": "http://example.com/",
+  "feed_url": "http://example.com/feed.json",
+  "favicon": "http://example.com/favicon.ico",
+  "icon": "http://example.com/icon.png",
+  "language": "en-US",
+  "authors": [{"name": "Jane Doe", "url": "http://example.com/jane"}],
+  "hubs": [{"type": "WebSub", "url": "http://hub.example.com/"}, {"type": "rssCloud", "url": "http://cloud.example.com/"}],
+  "items": [
+    {
+      "id": "http://example.com/1",
+      "url": "http://example.com/1",
+      "title": "Episode 1",
+      "content_html": "<p>The first episode</p>",
+      "summary": "The first episode",
+      "date_published": "2012-05-01T10:00:00Z",
+      "date_modified": "2012-05-02T10:00:00Z",
+      "tags": ["audio", "news"],
+      "image": "http://example.com/1.png",
+      "attachments": [
+        {"url": "http://example.com/1.mp3", "mime_type": "audio/mpeg", "size_in_bytes": 1024},
+        {"url": "http://example.com/1.ogg", "mime_type": "audio/ogg", "title": "Ogg", "duration_in_seconds": 60}
+      ]
+    },
+    {
+      "id": 2,
+      "content_text": "Fish & chips"
+    }
+  ]
+}`
+
+func TestParseJSONFeed(t *testing.T) {
+
+	rss, report, err := ParseJSONFeed(strings.NewReader(testJSONFeed))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing the JSON Feed\n", err)
+	}
+	expected := ConversionReport{
+		{Field: "Description", Conversion: ConversionSynthesized},
+		{Field: "Hubs[1]", Conversion: ConversionDropped},
+		{Field: "Atom.Icon", Conversion: ConversionKept},
+		{Field: "DC.Creators", Conversion: ConversionDropped},
+		{Field: "Items[0].Atom.Updated", Conversion: ConversionKept},
+		{Field: "Items[1].Description", Conversion: ConversionSynthesized},
+	}
+	if len(report) != len(expected) {
+		t.Fatalf("ParseJSONFeed reported %v notes expected %v:\n%v\n", len(report), len(expected), report)
+	}
+	for i := 0; i != len(expected); i++ {
+		if report[i].Field != expected[i].Field || report[i].Conversion != expected[i].Conversion {
+			t.Fatalf("ParseJSONFeed reported an unexpected note expected: %v/%v got: %v\n",
+				expected[i].Field, expected[i].Conversion, report[i])
+		}
+	}
+
+	if rss.Version != Version || rss.Title != "Example podcast" || rss.Link != "http://example.com/" ||
+		rss.Description != "Example podcast" || rss.Language != "en-us" ||
+		rss.Image == nil || rss.Image.Url != "http://example.com/icon.png" ||
+		rss.DC == nil || len(rss.DC.Creators) != 1 || rss.DC.Creators[0] != "Jane Doe" {
+		t.Fatalf("Unexpected channel %#v\n", rss)
+	}
+	if len(rss.AtomLinks) != 2 || rss.AtomLinks[0].Rel != AtomRelSelf || rss.AtomLinks[1].Rel != AtomRelHub ||
+		rss.AtomLinks[1].Href != "http://hub.example.com/" {
+		t.Fatalf("Unexpected atom links %#v\n", rss.AtomLinks)
+	}
+
+	item := rss.Items[0]
+	if item.Guid == nil || !item.Guid.IsPermaLink || item.Title != "Episode 1" ||
+		item.Content != "<p>The first episode</p>" || item.Description != "The first episode" ||
+		item.PubDate.Time.Year() != 2012 || item.Atom == nil || item.Atom.Updated.Time.Day() != 2 ||
+		len(item.Categories) != 2 || item.Categories[1].Category != "news" {
+		t.Fatalf("Unexpected item %#v\n", item)
+	}
+	if item.Enclosure == nil || item.Enclosure.Url != "http://example.com/1.mp3" || item.Enclosure.Length != 1024 ||
+		item.Media == nil || len(item.Media.Contents) != 1 || item.Media.Contents[0].Duration != "60" ||
+		len(item.Media.Thumbnails) != 1 {
+		t.Fatalf("Unexpected item attachments %#v %#v\n", item.Enclosure, item.Media)
+	}
+
+	item = rss.Items[1]
+	if item.Guid == nil || item.Guid.Guid != "2" || item.Guid.IsPermaLink || item.Content != "Fish &amp; chips" ||
+		item.Description != item.Content {
+		t.Fatalf("Unexpected item %#v\n", item)
+	}
+	if err := Verify(rss); err != nil {
+		t.Fatalf("Unexpected verify error %v\n", err)
+	}
+
+	// An invalid JSON Feed
+	if _, _, err := ParseJSONFeedWithOptions(strings.NewReader(`{"version": "1", "items": []}`),
+		ParseOptions{Verify: true}); err == nil {
+		t.Fatalf("Expected an error parsing an invalid JSON Feed\n")
+	}
+	if _, _, err := ParseJSONFeed(strings.NewReader(`{"version": `)); err == nil {
+		t.Fatalf("Expected an error parsing a truncated JSON Feed\n")
+	}
+}
+
+func TestParseJSONFeed10(t *testing.T) {
+
+	rss, _, err := ParseJSONFeed(strings.NewReader(`{"version": "https://jsonfeed.org/version/1", "title": "t",
+		"author": {"name": "Jane"}, "items": [{"id": "1", "content_text": "c", "author": {"name": "Joe"}}]}`))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing the JSON Feed\n", err)
+	}
+	if rss.DC == nil || rss.DC.Creators[0] != "Jane" || rss.Items[0].DC == nil || rss.Items[0].DC.Creators[0] != "Joe" {
+		t.Fatalf("Unexpected authors %#v %#v\n", rss.DC, rss.Items[0].DC)
+	}
+
+	// Only the 1.0 and 1.1 versions are accepted
+	if _, _, err := ParseJSONFeedWithOptions(strings.NewReader(`{"version": "https://jsonfeed.org/version/10", "title": "t",
+		"items": []}`), ParseOptions{Verify: true}); err == nil {
+		t.Fatalf("Expected an error parsing an unknown JSON Feed version\n")
+	}
+}
+
+func TestParseJSONFeedInvalidItems(t *testing.T) {
+
+	doc := `{"version": "https://jsonfeed.org/version/1.1", "title": "t", "items": [
+		{"id": "1", "content_text": "one", "date_published": "yesterday"},
+		{"id": "2", "content_text": "two", "url": "/two", "date_published": "2012-01-01T00:00:00Z"}]}`
+
+	// Without verification the valid values are converted and the bad date
+	// is reported as dropped
+	rss, report, err := ParseJSONFeed(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing the JSON Feed\n", err)
+	}
+	if len(rss.Items) != 2 || !rss.Items[0].PubDate.IsZero() || rss.Items[1].PubDate.Time.Year() != 2012 {
+		t.Fatalf("Unexpected items %#v\n", rss.Items)
+	}
+	dropped := report.Filter(ConversionDropped)
+	if len(dropped) != 1 || dropped[0].Field != "Items[0].PubDate" {
+		t.Fatalf("Unexpected dropped values %v\n", dropped)
+	}
+
+	// With verification the feed is rejected
+	_, _, err = ParseJSONFeedWithOptions(strings.NewReader(doc), ParseOptions{Verify: true})
+	errs, ok := err.(ValidationErrors)
+	if !ok || len(errs) != 2 || errs[0].Field != "Items[0].DatePublished" || errs[1].Field != "Items[1].Url" {
+		t.Fatalf("Unexpected verify errors %v\n", err)
+	}
+}
+
+func TestJSONFeedRss(t *testing.T) {
+
+	// A feed that wasn't verified reports the dates it can't convert
+	feed := &JSONFeed{Version: JSONFeedVersion, Title: "t", Description: "d",
+		Items: []JSONFeedItem{{Id: "1", ContentText: "c", DatePublished: "yesterday"}}}
+	rss, report := feed.Rss()
+	if !rss.Items[0].PubDate.IsZero() || len(report) != 2 || report[1].Field != "Items[0].PubDate" ||
+		report[1].Conversion != ConversionDropped {
+		t.Fatalf("Unexpected conversion of a bad date %#v %v\n", rss.Items[0].PubDate, report)
+	}
+}
+
+func TestWriteJSONFeed(t *testing.T) {
+
+	rss := &Rss{Version: Version, Title: "Example", Link: "http://example.com/", Description: "An example",
+		Copyright: "2012 Example", ManagingEditor: "jane@example.com (Jane Doe)",
+		AtomLinks: []AtomLink{{Href: "http://example.com/feed.json", Rel: AtomRelSelf},
+			{Href: "http://hub.example.com/", Rel: AtomRelHub}}}
+	rss.Items = []Item{
+		{Title: "One", Link: "http://example.com/1", Description: "<p>One</p>",
+			Guid: &Guid{Guid: "urn:1"}, PubDate: RssTimeFromString("Tue, 01 May 2012 10:00:00 GMT"),
+			Categories: []Category{{Category: "news", Domain: "http://example.com/tags"}},
+			Enclosure:  &Enclosure{Url: "http://example.com/1.mp3", Length: 1024, Type: "audio/mpeg"}},
+		{Title: "Two", Link: "http://example.com/2", Description: "Two", Content: "<p>Two</p>"},
+	}
+
+	var buf bytes.Buffer
+	report, err := WriteJSONFeed(&buf, rss)
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) writing the JSON Feed\n", err)
+	}
+	expected := ConversionReport{
+		{Field: "Copyright", Conversion: ConversionDropped},
+		{Field: "Items[0].Categories[0].Domain", Conversion: ConversionDropped},
+	}
+	if len(report) != len(expected) {
+		t.Fatalf("WriteJSONFeed reported %v notes expected %v:\n%v\n", len(report), len(expected), report)
+	}
+	for i := 0; i != len(expected); i++ {
+		if report[i].Field != expected[i].Field || report[i].Conversion != expected[i].Conversion {
+			t.Fatalf("WriteJSONFeed reported an unexpected note expected: %v/%v got: %v\n",
+				expected[i].Field, expected[i].Conversion, report[i])
+		}
+	}
+
+	feed := &JSONFeed{}
+	if err := json.Unmarshal(buf.Bytes(), feed); err != nil {
+		t.Fatalf("Unexpected error (%v) reading the written JSON Feed\n%v\n", err, buf.String())
+	}
+	if feed.Version != JSONFeedVersion || feed.FeedUrl != "http://example.com/feed.json" || len(feed.Hubs) != 1 ||
+		feed.Hubs[0].Type != JSONFeedWebSub || len(feed.Authors) != 1 || feed.Authors[0].Name != "Jane Doe" ||
+		feed.Authors[0].Url != "mailto:jane@example.com" || len(feed.Items) != 2 {
+		t.Fatalf("Unexpected JSON Feed %v\n", buf.String())
+	}
+
+	item := feed.Items[0]
+	if item.Id != "urn:1" || item.ContentHtml != "<p>One</p>" || item.Summary != "" ||
+		item.DatePublished != "2012-05-01T10:00:00Z" || len(item.Tags) != 1 || len(item.Attachments) != 1 ||
+		item.Attachments[0].MimeType != "audio/mpeg" || item.Attachments[0].SizeInBytes != 1024 {
+		t.Fatalf("Unexpected JSON Feed item %#v\n", item)
+	}
+	item = feed.Items[1]
+	if item.Id != "http://example.com/2" || item.ContentHtml != "<p>Two</p>" || item.Summary != "Two" {
+		t.Fatalf("Unexpected JSON Feed item %#v\n", item)
+	}
+
+	// Round trip
+	back, _, err := ParseJSONFeed(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing the written JSON Feed\n", err)
+	}
+	if back.Title != rss.Title || len(back.Items) != 2 || back.Items[0].Enclosure == nil ||
+		!back.Items[0].PubDate.Time.Equal(rss.Items[0].PubDate.Time) {
+		t.Fatalf("Unexpected round trip %#v\n", back)
+	}
+
+	// An item with only a title is written with the title as its text
+	buf.Reset()
+	titleOnly := &Rss{Version: Version, Title: "t", Link: "http://example.com/", Description: "d",
+		Items: []Item{{Title: "Fish & chips", Link: "http://example.com/1"}}}
+	report, err = WriteJSONFeed(&buf, titleOnly)
+	if err != nil || len(report) != 1 || report[0].Field != "Items[0].ContentText" ||
+		report[0].Conversion != ConversionSynthesized {
+		t.Fatalf("Unexpected error (%v) writing a title only item %v\n", err, report)
+	}
+	feed = &JSONFeed{}
+	if err := json.Unmarshal(buf.Bytes(), feed); err != nil || feed.Items[0].ContentText != "Fish & chips" ||
+		feed.Items[0].ContentHtml != "" {
+		t.Fatalf("Unexpected title only item (%v) %v\n", err, buf.String())
+	}
+
+	// An item without an id can't be written
+	rss.Items = append(rss.Items, Item{Description: "No link"})
+	if _, err := WriteJSONFeed(&buf, rss); err == nil {
+		t.Fatalf("Expected an error writing an item without an id\n")
+	}
+}
+
+func TestVerifyJSONFeed(t *testing.T) {
+
+	feed := &JSONFeed{Version: "http://example.com/", HomePageUrl: "example.com",
+		Authors: []JSONFeedAuthor{{}},
+		Hubs:    []JSONFeedHub{{Url: "http://hub.example.com/"}},
+		Items: []JSONFeedItem{
+			{Id: "1", DatePublished: "May 1 2012",
+				Attachments: []JSONFeedAttachment{{Url: "http://example.com/1.mp3", SizeInBytes: -1}}},
+			{Id: "1", ContentText: "c"},
+			{ContentText: "c", Author: &JSONFeedAuthor{Avatar: "avatar.png"}},
+		}}
+	expected := ValidationErrors{
+		{Field: "Version", Code: CodeVersion},
+		{Field: "Title", Code: CodeRequired},
+		{Field: "HomePageUrl", Code: CodeURLNotAbsolute},
+		{Field: "Authors[0]", Code: CodeRequired},
+		{Field: "Hubs[0].Type", Code: CodeRequired},
+		{Field: "Items[0].ContentHtml", Code: CodeRequired},
+		{Field: "Items[0].DatePublished", Code: CodeDate},
+		{Field: "Items[0].Attachments[0].MimeType", Code: CodeRequired},
+		{Field: "Items[0].Attachments[0].SizeInBytes", Code: CodeRange},
+		{Field: "Items[1].Id", Code: CodeDuplicateId},
+		{Field: "Items[2].Id", Code: CodeRequired},
+		{Field: "Items[2].Author.Avatar", Code: CodeURLNotAbsolute},
+	}
+	errs := VerifyJSONFeed(feed)
+	if len(errs) != len(expected) {
+		t.Fatalf("VerifyJSONFeed returned %v errors expected %v:\n%v\n", len(errs), len(expected), errs)
+	}
+	for i := 0; i != len(expected); i++ {
+		if errs[i].Field != expected[i].Field || errs[i].Code != expected[i].Code {
+			t.Fatalf("VerifyJSONFeed returned an unexpected error expected: %v/%v got: %v\n",
+				expected[i].Field, expected[i].Code, errs[i])
+		}
+	}
+
+	var id JSONFeedId
+	if err := json.Unmarshal([]byte(`12.5`), &id); err != nil || id != "12.5" {
+		t.Fatalf("Unexpected id %v (%v)\n", id, err)
+	}
+	if err := json.Unmarshal([]byte(`true`), &id); err == nil {
+		t.Fatalf("Expected an error reading a boolean id\n")
+	}
+}
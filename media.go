@@ -0,0 +1,398 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// The namespace of the Media RSS elements, see
+// http://www.rssboard.org/media-rss
+const MediaNamespace = "http://search.yahoo.com/mrss/"
+
+// The Media RSS elements of an item
+type MediaItem struct {
+	// Optional. Groups of media objects that are renditions of the same
+	// content
+	Groups []MediaGroup `xml:"http://search.yahoo.com/mrss/ group"`
+
+	// Optional. The media objects
+	Contents []MediaContent `xml:"http://search.yahoo.com/mrss/ content"`
+
+	// Optional. Images representing the media
+	Thumbnails []MediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+
+	// Optional. The title of the media
+	Title *MediaText `xml:"http://search.yahoo.com/mrss/ title"`
+
+	// Optional. A description of the media
+	Description *MediaText `xml:"http://search.yahoo.com/mrss/ description"`
+
+	// Optional. The people and organizations that created the media
+	Credits []MediaCredit `xml:"http://search.yahoo.com/mrss/ credit"`
+}
+
+// Media objects that are different renditions of the same content
+type MediaGroup struct {
+	// Required. The renditions
+	Contents []MediaContent `xml:"http://search.yahoo.com/mrss/ content"`
+
+	// Optional. Images representing the media
+	Thumbnails []MediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+
+	// Optional. The title of the media
+	Title *MediaText `xml:"http://search.yahoo.com/mrss/ title"`
+
+	// Optional. A description of the media
+	Description *MediaText `xml:"http://search.yahoo.com/mrss/ description"`
+
+	// Optional. The people and organizations that created the media
+	Credits []MediaCredit `xml:"http://search.yahoo.com/mrss/ credit"`
+
+	// Optional. The child elements that rssgo doesn't support, such as
+	// <media:player>
+	Extensions []Element `xml:",any"`
+}
+
+// A media object. The numeric attributes are read with
+// MediaContent.FileSizeValue and the other Value methods.
+type MediaContent struct {
+	// Required unless there's a <media:player> element. The URL of the media
+	// object
+	Url string `xml:"url,attr,omitempty"`
+
+	// Optional. The size in bytes
+	FileSize string `xml:"fileSize,attr,omitempty"`
+
+	// Optional. The MIME type
+	Type string `xml:"type,attr,omitempty"`
+
+	// Optional. One of image, audio, video, document or executable
+	Medium string `xml:"medium,attr,omitempty"`
+
+	// Optional. "true" for the default rendition in a group, see
+	// MediaContent.IsDefaultValue
+	IsDefault string `xml:"isDefault,attr,omitempty"`
+
+	// Optional. One of sample, full or nonstop
+	Expression string `xml:"expression,attr,omitempty"`
+
+	// Optional. The kilobits per second
+	Bitrate string `xml:"bitrate,attr,omitempty"`
+
+	// Optional. The frames per second
+	Framerate string `xml:"framerate,attr,omitempty"`
+
+	// Optional. The samples per second in kHz
+	SamplingRate string `xml:"samplingrate,attr,omitempty"`
+
+	// Optional. The number of audio channels
+	Channels string `xml:"channels,attr,omitempty"`
+
+	// Optional. The length in whole seconds
+	Duration string `xml:"duration,attr,omitempty"`
+
+	// Optional. The height in pixels
+	Height string `xml:"height,attr,omitempty"`
+
+	// Optional. The width in pixels
+	Width string `xml:"width,attr,omitempty"`
+
+	// Optional. The language of the media, as in Rss.Language
+	Lang string `xml:"lang,attr,omitempty"`
+
+	// Optional. Images representing the media
+	Thumbnails []MediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+
+	// Optional. The title of the media
+	Title *MediaText `xml:"http://search.yahoo.com/mrss/ title"`
+
+	// Optional. A description of the media
+	Description *MediaText `xml:"http://search.yahoo.com/mrss/ description"`
+
+	// Optional. The people and organizations that created the media
+	Credits []MediaCredit `xml:"http://search.yahoo.com/mrss/ credit"`
+
+	// Optional. The child elements that rssgo doesn't support, such as
+	// <media:player>
+	Extensions []Element `xml:",any"`
+}
+
+// An image representing a media object
+type MediaThumbnail struct {
+	// Required. The URL of the image
+	Url string `xml:"url,attr"`
+
+	// Optional. The height in pixels, see MediaThumbnail.HeightValue
+	Height string `xml:"height,attr,omitempty"`
+
+	// Optional. The width in pixels, see MediaThumbnail.WidthValue
+	Width string `xml:"width,attr,omitempty"`
+
+	// Optional. The time offset in the media that the image is from, in the
+	// NTP format, for example "12:05:01.123"
+	Time string `xml:"time,attr,omitempty"`
+}
+
+// A media title or description
+type MediaText struct {
+	// Required. The text
+	Text string `xml:",chardata"`
+
+	// Optional. Either plain, the default, or html
+	Type string `xml:"type,attr,omitempty"`
+}
+
+// A person or organization that created a media object
+type MediaCredit struct {
+	// Required. The name
+	Credit string `xml:",chardata"`
+
+	// Optional. Their role, for example "producer"
+	Role string `xml:"role,attr,omitempty"`
+
+	// Optional. The URI that identifies the role scheme, defaults to
+	// urn:ebu
+	Scheme string `xml:"scheme,attr,omitempty"`
+}
+
+// Writes the elements without an enclosing element.
+func (m MediaItem) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type plain MediaItem
+	return marshalFlat(e, plain(m))
+}
+
+// Returns every rendition of the item's media: the media:content elements,
+// including those in groups, and the enclosure. The enclosure is returned
+// first unless it has the same URL as a media:content element.
+func (item *Item) Renditions() []MediaContent {
+	var renditions []MediaContent
+	if item.Media != nil {
+		renditions = append(renditions, item.Media.Contents...)
+		for _, group := range item.Media.Groups {
+			renditions = append(renditions, group.Contents...)
+		}
+	}
+
+	if item.Enclosure != nil {
+		for _, content := range renditions {
+			if content.Url == item.Enclosure.Url {
+				return renditions
+			}
+		}
+		enclosure := MediaContent{Url: item.Enclosure.Url, Type: item.Enclosure.Type}
+		if item.Enclosure.Length != 0 {
+			enclosure.FileSize = strconv.FormatInt(item.Enclosure.Length, 10)
+		}
+		renditions = append([]MediaContent{enclosure}, renditions...)
+	}
+	return renditions
+}
+
+// Returns the size in bytes and true, or false if the size isn't set or isn't
+// an integer.
+func (c *MediaContent) FileSizeValue() (int64, bool) {
+	return parseSize(c.FileSize)
+}
+
+// Returns true if this is the default rendition in a group. False is returned
+// if isDefault isn't set, an error is returned if it isn't a boolean.
+func (c *MediaContent) IsDefaultValue() (bool, error) {
+	text := strings.TrimSpace(c.IsDefault)
+	if text == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(text)
+}
+
+// Returns the kilobits per second and true, or false if the bitrate isn't set
+// or isn't a number.
+func (c *MediaContent) BitrateValue() (float64, bool) {
+	return parseDecimal(c.Bitrate)
+}
+
+// Returns the frames per second and true, or false if the framerate isn't set
+// or isn't a number.
+func (c *MediaContent) FramerateValue() (float64, bool) {
+	return parseDecimal(c.Framerate)
+}
+
+// Returns the samples per second in kHz and true, or false if the sampling
+// rate isn't set or isn't a number.
+func (c *MediaContent) SamplingRateValue() (float64, bool) {
+	return parseDecimal(c.SamplingRate)
+}
+
+// Returns the number of audio channels and true, or false if the channels
+// aren't set or aren't an integer.
+func (c *MediaContent) ChannelsValue() (int, bool) {
+	return parseCount(c.Channels)
+}
+
+// Returns the length in seconds and true, or false if the duration isn't set
+// or isn't an integer.
+func (c *MediaContent) DurationValue() (int, bool) {
+	return parseCount(c.Duration)
+}
+
+// Returns the height in pixels and true, or false if the height isn't set or
+// isn't an integer.
+func (c *MediaContent) HeightValue() (int, bool) {
+	return parseCount(c.Height)
+}
+
+// Returns the width in pixels and true, or false if the width isn't set or
+// isn't an integer.
+func (c *MediaContent) WidthValue() (int, bool) {
+	return parseCount(c.Width)
+}
+
+// Returns the height in pixels and true, or false if the height isn't set or
+// isn't an integer.
+func (t *MediaThumbnail) HeightValue() (int, bool) {
+	return parseCount(t.Height)
+}
+
+// Returns the width in pixels and true, or false if the width isn't set or
+// isn't an integer.
+func (t *MediaThumbnail) WidthValue() (int, bool) {
+	return parseCount(t.Width)
+}
+
+// Parses the text of an attribute that holds a size in bytes, such as
+// fileSize.
+func parseSize(text string) (int64, bool) {
+	n, err := strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+	return n, err == nil
+}
+
+// Parses the text of an attribute that holds a decimal number, such as
+// bitrate. strconv.ParseFloat accepts NaN and infinities, which aren't
+// numbers here.
+func parseDecimal(text string) (float64, bool) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	return f, err == nil && !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// Verifies the item's Media RSS elements.
+func (v *verifier) verifyMediaItem(path string, m *MediaItem) {
+	for i := 0; i != len(m.Groups); i++ {
+		group := &m.Groups[i]
+		field := fmt.Sprintf("%v.Groups[%v]", path, i)
+		if len(group.Contents) == 0 {
+			v.add(field+".Contents", CodeRequired, "A media group must have media content.")
+		}
+		defaults := 0
+		for j := 0; j != len(group.Contents); j++ {
+			v.verifyMediaContent(fmt.Sprintf("%v.Contents[%v]", field, j), &group.Contents[j])
+			if isDefault, _ := group.Contents[j].IsDefaultValue(); isDefault {
+				defaults++
+			}
+		}
+		if defaults > 1 {
+			v.add(field+".Contents", CodeMediaDefault, "Only one media content in a group can be the default.")
+		}
+		v.verifyMediaElements(field, group.Thumbnails, group.Title, group.Description, group.Credits)
+	}
+
+	for i := 0; i != len(m.Contents); i++ {
+		v.verifyMediaContent(fmt.Sprintf("%v.Contents[%v]", path, i), &m.Contents[i])
+	}
+	v.verifyMediaElements(path, m.Thumbnails, m.Title, m.Description, m.Credits)
+}
+
+// Verifies a media:content element.
+func (v *verifier) verifyMediaContent(path string, c *MediaContent) {
+	if c.Url != "" || !hasElement(c.Extensions, MediaNamespace, "player") {
+		v.verifyURL(path+".Url", "media content url", c.Url)
+	}
+
+	if c.Medium != "" && !containsString([]string{"image", "audio", "video", "document", "executable"}, c.Medium) {
+		v.add(path+".Medium", CodeMediaValue, "The media medium must be image, audio, video, document or executable.")
+	}
+
+	if c.Expression != "" && !containsString([]string{"sample", "full", "nonstop"}, c.Expression) {
+		v.add(path+".Expression", CodeMediaValue, "The media expression must be sample, full or nonstop.")
+	}
+
+	if _, err := c.IsDefaultValue(); err != nil {
+		v.add(path+".IsDefault", CodeMediaValue, "The media isDefault must be true or false.")
+	}
+
+	size, sizeOk := c.FileSizeValue()
+	bitrate, bitrateOk := c.BitrateValue()
+	framerate, framerateOk := c.FramerateValue()
+	samplingRate, samplingRateOk := c.SamplingRateValue()
+	channels, channelsOk := c.ChannelsValue()
+	duration, durationOk := c.DurationValue()
+	height, heightOk := c.HeightValue()
+	width, widthOk := c.WidthValue()
+	for _, value := range []struct {
+		name  string
+		text  string
+		valid bool
+		kind  string
+	}{
+		{"FileSize", c.FileSize, sizeOk && size >= 0, "an integer"},
+		{"Bitrate", c.Bitrate, bitrateOk && bitrate >= 0, "a number"},
+		{"Framerate", c.Framerate, framerateOk && framerate >= 0, "a number"},
+		{"SamplingRate", c.SamplingRate, samplingRateOk && samplingRate >= 0, "a number"},
+		{"Channels", c.Channels, channelsOk && channels >= 0, "an integer"},
+		{"Duration", c.Duration, durationOk && duration >= 0, "an integer"},
+		{"Height", c.Height, heightOk && height >= 0, "an integer"},
+		{"Width", c.Width, widthOk && width >= 0, "an integer"},
+	} {
+		if value.text != "" && !value.valid {
+			v.add(path+"."+value.name, CodeRange,
+				fmt.Sprintf("The media content %v must be %v that isn't negative.", value.name, value.kind))
+		}
+	}
+
+	v.verifyMediaElements(path, c.Thumbnails, c.Title, c.Description, c.Credits)
+}
+
+// Verifies the elements that can be used at the item, group and content
+// levels.
+func (v *verifier) verifyMediaElements(path string, thumbnails []MediaThumbnail, title, description *MediaText, credits []MediaCredit) {
+	for i := 0; i != len(thumbnails); i++ {
+		field := fmt.Sprintf("%v.Thumbnails[%v]", path, i)
+		v.verifyURL(field+".Url", "media thumbnail url", thumbnails[i].Url)
+		height, heightOk := thumbnails[i].HeightValue()
+		width, widthOk := thumbnails[i].WidthValue()
+		if (thumbnails[i].Height != "" && (!heightOk || height < 0)) ||
+			(thumbnails[i].Width != "" && (!widthOk || width < 0)) {
+			v.add(field, CodeRange, "The media thumbnail size must be integers that aren't negative.")
+		}
+	}
+
+	for _, text := range []struct {
+		name string
+		text *MediaText
+	}{{"Title", title}, {"Description", description}} {
+		if text.text != nil && text.text.Type != "" && text.text.Type != "plain" && text.text.Type != "html" {
+			v.add(path+"."+text.name+".Type", CodeMediaValue, "The media text type must be plain or html.")
+		}
+	}
+
+	for i := 0; i != len(credits); i++ {
+		if credits[i].Credit == "" {
+			v.add(fmt.Sprintf("%v.Credits[%v].Credit", path, i), CodeRequired, "The media credit must be set.")
+		}
+	}
+}
+
+// Returns true if one of the elements has the name.
+func hasElement(elements []Element, space, local string) bool {
+	for _, el := range elements {
+		if el.XMLName.Space == space && el.XMLName.Local == local {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestMedia(t *testing.T) {
+
+	doc := `<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/"><channel>
+<title>title</title><link>http://github.com/efarrer/rssgo/</link><description>Videos</description>
+<item><title>one</title>
+<enclosure url="http://example.com/720.mp4" length="2048" type="video/mp4"/>
+<media:group>
+<media:content url="http://example.com/1080.mp4" type="video/mp4" medium="video" isDefault="true" width="1920" height="1080" bitrate="4500" framerate="29.97" duration="120"/>
+<media:content url="http://example.com/720.mp4" fileSize="2048" type="video/mp4" medium="video" width="1280" height="720"/>
+<media:player url="http://example.com/player"/>
+</media:group>
+<media:content url="http://example.com/audio.mp3" medium="audio" samplingrate="44.1" channels="2"><media:title type="plain">Audio only</media:title></media:content>
+<media:thumbnail url="http://example.com/thumb.jpg" width="120" height="90" time="12:05:01.123"/>
+<media:title>The video</media:title>
+<media:description type="html">&lt;b&gt;A video&lt;/b&gt;</media:description>
+<media:credit role="producer" scheme="urn:ebu">Jane Doe</media:credit>
+<media:rating>nonadult</media:rating>
+</item>
+<item><title>two</title><enclosure url="http://example.com/two.mp3" length="1" type="audio/mpeg"/></item>
+</channel></rss>`
+
+	rss, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing the document\n", err)
+	}
+	m := rss.Items[0].Media
+	if m == nil || len(m.Groups) != 1 || len(m.Groups[0].Contents) != 2 || len(m.Contents) != 1 ||
+		len(m.Thumbnails) != 1 || m.Title.Text != "The video" || m.Description.Type != "html" ||
+		m.Description.Text != "<b>A video</b>" || m.Credits[0].Role != "producer" {
+		t.Fatalf("Unexpected media %#v\n", m)
+	}
+	c := m.Groups[0].Contents[0]
+	if isDefault, err := c.IsDefaultValue(); !isDefault || err != nil || c.Width != "1920" || c.Framerate != "29.97" || c.Bitrate != "4500" || c.Duration != "120" {
+		t.Fatalf("Unexpected media content %#v\n", c)
+	}
+	if width, ok := c.WidthValue(); !ok || width != 1920 {
+		t.Fatalf("Unexpected media content width %v %v\n", width, ok)
+	}
+	if framerate, ok := c.FramerateValue(); !ok || framerate != 29.97 {
+		t.Fatalf("Unexpected media content framerate %v %v\n", framerate, ok)
+	}
+	if _, ok := c.FileSizeValue(); ok {
+		t.Fatalf("Unexpected media content file size for a missing fileSize\n")
+	}
+	if len(m.Groups[0].Extensions) != 1 || m.Groups[0].Extensions[0].XMLName.Local != "player" {
+		t.Fatalf("Unexpected media group extensions %#v\n", m.Groups[0].Extensions)
+	}
+	if rate, ok := m.Contents[0].SamplingRateValue(); !ok || rate != 44.1 || m.Contents[0].Title.Text != "Audio only" ||
+		m.Thumbnails[0].Time != "12:05:01.123" {
+		t.Fatalf("Unexpected media %#v\n", m)
+	}
+	if len(rss.Items[0].Extensions) != 1 || rss.Items[0].Extensions[0].XMLName.Local != "rating" {
+		t.Fatalf("Unexpected item extensions %#v\n", rss.Items[0].Extensions)
+	}
+	if err := Verify(rss); err != nil {
+		t.Fatalf("Unexpected verify error %v\n", err)
+	}
+
+	// A bad isDefault doesn't stop the document from being parsed
+	bad, err := Parse(strings.NewReader(strings.Replace(doc, `isDefault="true"`, `isDefault="yes"`, 1)))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing a bad isDefault\n", err)
+	}
+	if _, err := bad.Items[0].Media.Groups[0].Contents[0].IsDefaultValue(); err == nil {
+		t.Fatalf("Expected an error for a bad isDefault\n")
+	}
+
+	// The enclosure is a duplicate of a media:content
+	renditions := rss.Items[0].Renditions()
+	if len(renditions) != 3 || renditions[0].Url != "http://example.com/audio.mp3" ||
+		renditions[1].Url != "http://example.com/1080.mp4" || renditions[2].Url != "http://example.com/720.mp4" {
+		t.Fatalf("Unexpected renditions %#v\n", renditions)
+	}
+	renditions = rss.Items[1].Renditions()
+	if len(renditions) != 1 || renditions[0].Url != "http://example.com/two.mp3" ||
+		renditions[0].FileSize != "1" || renditions[0].Type != "audio/mpeg" {
+		t.Fatalf("Unexpected renditions %#v\n", renditions)
+	}
+	if len((&Item{}).Renditions()) != 0 {
+		t.Fatalf("An item without media should have no renditions\n")
+	}
+
+	data, err := xml.Marshal(rss)
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) marshalling the document\n", err)
+	}
+	for _, text := range []string{
+		`<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/">`,
+		`<media:group><media:content url="http://example.com/1080.mp4" type="video/mp4" medium="video" isDefault="true" bitrate="4500" framerate="29.97" duration="120" height="1080" width="1920"></media:content>`,
+		`<media:player url="http://example.com/player"></media:player></media:group>`,
+		`<media:content url="http://example.com/audio.mp3" medium="audio" samplingrate="44.1" channels="2"><media:title type="plain">Audio only</media:title></media:content>`,
+		`<media:thumbnail url="http://example.com/thumb.jpg" height="90" width="120" time="12:05:01.123"></media:thumbnail>`,
+		`<media:title>The video</media:title><media:description type="html">&lt;b&gt;A video&lt;/b&gt;</media:description>`,
+		`<media:credit role="producer" scheme="urn:ebu">Jane Doe</media:credit><media:rating>nonadult</media:rating></item>`,
+	} {
+		if !strings.Contains(string(data), text) {
+			t.Fatalf("Marshalled document is missing %v: %v\n", text, string(data))
+		}
+	}
+
+	// Violations
+	rss.Items[0].Media = &MediaItem{
+		Groups: []MediaGroup{{}, {Contents: []MediaContent{
+			{Url: "http://example.com/a.mp4", IsDefault: "true", Medium: "film"},
+			{Url: "http://example.com/b.mp4", IsDefault: "1", Width: "-1"}}}},
+		Contents: []MediaContent{
+			{Expression: "partial", Bitrate: "128k", Duration: "12.5", FileSize: "NaN"},
+			{Extensions: []Element{{XMLName: xml.Name{Space: MediaNamespace, Local: "player"}}},
+				IsDefault: "yes", Framerate: "NaN", Channels: "2"}},
+		Thumbnails:  []MediaThumbnail{{Url: "thumb.jpg", Height: "tall"}},
+		Description: &MediaText{Text: "text", Type: "markdown"},
+		Credits:     []MediaCredit{{Role: "producer"}}}
+
+	expected := ValidationErrors{
+		{Field: "Items[0].Media.Groups[0].Contents", Code: CodeRequired},
+		{Field: "Items[0].Media.Groups[1].Contents[0].Medium", Code: CodeMediaValue},
+		{Field: "Items[0].Media.Groups[1].Contents[1].Width", Code: CodeRange},
+		{Field: "Items[0].Media.Groups[1].Contents", Code: CodeMediaDefault},
+		{Field: "Items[0].Media.Contents[0].Url", Code: CodeRequired},
+		{Field: "Items[0].Media.Contents[0].Expression", Code: CodeMediaValue},
+		{Field: "Items[0].Media.Contents[0].FileSize", Code: CodeRange},
+		{Field: "Items[0].Media.Contents[0].Bitrate", Code: CodeRange},
+		{Field: "Items[0].Media.Contents[0].Duration", Code: CodeRange},
+		{Field: "Items[0].Media.Contents[1].IsDefault", Code: CodeMediaValue},
+		{Field: "Items[0].Media.Contents[1].Framerate", Code: CodeRange},
+		{Field: "Items[0].Media.Thumbnails[0].Url", Code: CodeURLNotAbsolute},
+		{Field: "Items[0].Media.Thumbnails[0]", Code: CodeRange},
+		{Field: "Items[0].Media.Description.Type", Code: CodeMediaValue},
+		{Field: "Items[0].Media.Credits[0].Credit", Code: CodeRequired},
+	}
+	errs := VerifyAll(rss)
+	if len(errs) != len(expected) {
+		t.Fatalf("VerifyAll returned %v errors expected %v: %v\n", len(errs), len(expected), errs)
+	}
+	for i := 0; i != len(expected); i++ {
+		if errs[i].Field != expected[i].Field || errs[i].Code != expected[i].Code {
+			t.Fatalf("VerifyAll returned unexpected error expected: %v/%v got: %v/%v\n",
+				expected[i].Field, expected[i].Code, errs[i].Field, errs[i].Code)
+		}
+	}
+}
+
+func TestMediaNumbers(t *testing.T) {
+
+	rss, err := Parse(strings.NewReader(`<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/">
+<channel><title>title</title><link>http://example.com/</link><description>d</description>
+<item><title>one</title>
+<media:content url="http://example.com/a.mp3" bitrate="128k" duration="12.5" fileSize=" 2048 "/>
+<media:thumbnail url="http://example.com/a.jpg" width="wide"/>
+</item></channel></rss>`))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing bad media numbers\n", err)
+	}
+	c := rss.Items[0].Media.Contents[0]
+	if _, ok := c.BitrateValue(); ok || c.Bitrate != "128k" {
+		t.Fatalf("Unexpected bitrate %#v\n", c)
+	}
+	if _, ok := c.DurationValue(); ok || c.Duration != "12.5" {
+		t.Fatalf("Unexpected duration %#v\n", c)
+	}
+	if size, ok := c.FileSizeValue(); !ok || size != 2048 {
+		t.Fatalf("Unexpected file size %v %v\n", size, ok)
+	}
+	if _, ok := rss.Items[0].Media.Thumbnails[0].WidthValue(); ok {
+		t.Fatalf("Unexpected thumbnail width %#v\n", rss.Items[0].Media.Thumbnails[0])
+	}
+
+	errs := VerifyAll(rss)
+	if len(errs) != 3 || errs[0].Field != "Items[0].Media.Contents[0].Bitrate" ||
+		errs[1].Field != "Items[0].Media.Contents[0].Duration" || errs[2].Field != "Items[0].Media.Thumbnails[0]" {
+		t.Fatalf("Unexpected verify errors %v\n", errs)
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// An extension namespace whose elements are decoded into Go values instead of
+// Extensions. See rssgo.RegisterModule
+type Module struct {
+	// Required. The namespace URI
+	Namespace string
+
+	// Required. The prefix used when writing a feed that doesn't declare the
+	// namespace
+	Prefix string
+
+	// Optional. A struct, or a pointer to one, whose fields hold the
+	// namespace's child elements of <channel>. The fields are tagged with the
+	// namespace, for example `xml:"http://example.com/ns rating,omitempty"`
+	Channel interface{}
+
+	// Optional. Like Channel, for the namespace's child elements of <item>
+	Item interface{}
+}
+
+// The values of the registered modules' elements keyed by namespace URI. Each
+// value is a pointer to the module's Channel or Item type
+type Modules map[string]interface{}
+
+// A registered module with the fields of its types
+type registeredModule struct {
+	channel       reflect.Type
+	channelFields []elementField
+	item          reflect.Type
+	itemFields    []elementField
+}
+
+// The registered modules keyed by namespace URI
+var modules = map[string]*registeredModule{}
+
+// Guards modules and knownPrefixes, which RegisterModule adds to
+var moduleLock sync.RWMutex
+
+// Registers a module so that its elements are parsed into the Modules of Rss
+// and Item, and written back with its prefix. Elements of the namespace that
+// don't match a field of the module's types are kept in Extensions. The
+// namespaces and prefixes of rssgo's own extensions, and of modules that are
+// already registered, can't be registered. RegisterModule is usually called
+// from an init function, feeds that are being parsed or written when it's
+// called may not use the module.
+func RegisterModule(m Module) error {
+	if m.Namespace == "" || m.Namespace == xmlNamespace {
+		return errors.New(fmt.Sprintf("Bad module namespace %#v", m.Namespace))
+	}
+	if m.Prefix == "" || m.Prefix == "xml" || m.Prefix == "xmlns" {
+		return errors.New(fmt.Sprintf("Bad module prefix %#v", m.Prefix))
+	}
+
+	moduleLock.Lock()
+	defer moduleLock.Unlock()
+	if _, ok := knownPrefixes[m.Namespace]; ok {
+		return errors.New(fmt.Sprintf("The namespace %v is already registered", m.Namespace))
+	}
+	for namespace, prefix := range knownPrefixes {
+		if prefix == m.Prefix {
+			return errors.New(fmt.Sprintf("The prefix %v is already used by %v", m.Prefix, namespace))
+		}
+	}
+	if m.Channel == nil && m.Item == nil {
+		return errors.New(fmt.Sprintf("The module %v must have a Channel or Item type", m.Namespace))
+	}
+
+	module := &registeredModule{}
+	var err error
+	if m.Channel != nil {
+		if module.channel, module.channelFields, err = moduleType(m.Namespace, m.Channel); err != nil {
+			return err
+		}
+	}
+	if m.Item != nil {
+		if module.item, module.itemFields, err = moduleType(m.Namespace, m.Item); err != nil {
+			return err
+		}
+	}
+
+	modules[m.Namespace] = module
+	knownPrefixes[m.Namespace] = m.Prefix
+	return nil
+}
+
+// Returns the struct type of v and its fields.
+func moduleType(namespace string, v interface{}) (reflect.Type, []elementField, error) {
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, nil, errors.New(fmt.Sprintf("The module %v type %v isn't a struct", namespace, t))
+	}
+	fields := elementFields(t, "")
+	if len(fields) == 0 {
+		return nil, nil, errors.New(fmt.Sprintf("The module %v type %v has no element fields", namespace, t))
+	}
+	// Elements of other namespaces would never be decoded into the module
+	for _, field := range fields {
+		if field.name.Space != namespace || field.extension != nil {
+			return nil, nil, errors.New(fmt.Sprintf("The module %v type %v field %v isn't in the module's namespace",
+				namespace, t, t.Field(field.index).Name))
+		}
+	}
+	return t, fields, nil
+}
+
+// Decodes a child element into the registered module of its namespace,
+// allocating the module's value in values if needed. Returns false, without
+// reading the element, if there's no matching module field.
+func decodeModule(d *xml.Decoder, values *Modules, item bool, parent xml.Name, start *xml.StartElement) (bool, error) {
+	moduleLock.RLock()
+	module, ok := modules[start.Name.Space]
+	moduleLock.RUnlock()
+	if !ok {
+		return false, nil
+	}
+	t, fields := module.channel, module.channelFields
+	if item {
+		t, fields = module.item, module.itemFields
+	}
+	if t == nil {
+		return false, nil
+	}
+	if _, ok := findField(fields, parent, start.Name); !ok {
+		return false, nil
+	}
+
+	value, ok := (*values)[start.Name.Space]
+	if !ok {
+		value = reflect.New(t).Interface()
+		if *values == nil {
+			*values = Modules{}
+		}
+		(*values)[start.Name.Space] = value
+	}
+	return decodeField(d, reflect.ValueOf(value).Elem(), fields, parent, start)
+}
+
+// Writes the elements of each module, in namespace order, without an
+// enclosing element. Nil values are skipped.
+func (m Modules) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	namespaces := make([]string, 0, len(m))
+	for namespace := range m {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	for _, namespace := range namespaces {
+		if m[namespace] == nil {
+			continue
+		}
+		if v := reflect.ValueOf(m[namespace]); v.Kind() == reflect.Ptr && v.IsNil() {
+			continue
+		}
+		if err := marshalFlat(e, m[namespace]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+const testModuleNamespace = "http://example.com/module"
+
+type testModuleChannel struct {
+	Owner string `xml:"http://example.com/module owner,omitempty"`
+}
+
+type testModuleItem struct {
+	Rating int      `xml:"http://example.com/module rating,omitempty"`
+	Tags   []string `xml:"http://example.com/module tag"`
+	Region *struct {
+		Code string `xml:"code,attr"`
+		Name string `xml:",chardata"`
+	} `xml:"http://example.com/module region"`
+}
+
+func init() {
+	if err := RegisterModule(Module{Namespace: testModuleNamespace, Prefix: "mod",
+		Channel: testModuleChannel{}, Item: &testModuleItem{}}); err != nil {
+		panic(err)
+	}
+}
+
+func TestRegisterModule(t *testing.T) {
+
+	for _, m := range []Module{
+		{Prefix: "x", Item: testModuleItem{}},
+		{Namespace: "http://example.com/x", Item: testModuleItem{}},
+		{Namespace: "http://example.com/x", Prefix: "xmlns", Item: testModuleItem{}},
+		{Namespace: testModuleNamespace, Prefix: "x", Item: testModuleItem{}},
+		{Namespace: "http://example.com/x", Prefix: "x"},
+		{Namespace: "http://example.com/x", Prefix: "x", Channel: "string"},
+		{Namespace: "http://example.com/x", Prefix: "x", Item: struct{ A string }{}},
+		{Namespace: ITunesNamespace, Prefix: "x", Item: testModuleItem{}},
+		{Namespace: "http://example.com/x", Prefix: "itunes", Item: testModuleItem{}},
+		{Namespace: "http://example.com/x", Prefix: "mod", Item: testModuleItem{}},
+		{Namespace: "http://example.com/x", Prefix: "x", Item: testModuleChannel{}},
+		{Namespace: "http://example.com/x", Prefix: "x", Item: struct {
+			A string `xml:"http://example.com/x a"`
+			B string `xml:"b"`
+		}{}},
+	} {
+		if err := RegisterModule(m); err == nil {
+			t.Fatalf("Expected an error registering %#v\n", m)
+		}
+	}
+	if _, ok := modules["http://example.com/x"]; ok {
+		t.Fatalf("Unexpected registered module\n")
+	}
+	if knownPrefixes[ITunesNamespace] != "itunes" {
+		t.Fatalf("The iTunes prefix was changed to %v\n", knownPrefixes[ITunesNamespace])
+	}
+}
+
+func TestModules(t *testing.T) {
+
+	doc := `<rss version="2.0" xmlns:m="http://example.com/module"><channel>
+<title>title</title><link>http://github.com/efarrer/rssgo/</link><description>A blog</description>
+<m:owner>Jane</m:owner>
+<m:unknown>kept</m:unknown>
+<item><title>one</title><m:rating>5</m:rating><m:tag>a</m:tag><m:tag>b</m:tag>
+<m:region code="us-or">Oregon</m:region></item>
+<item><title>two</title></item>
+</channel></rss>`
+
+	rss, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing the document\n", err)
+	}
+	channel, ok := rss.Modules[testModuleNamespace].(*testModuleChannel)
+	if !ok || channel.Owner != "Jane" {
+		t.Fatalf("Unexpected channel modules %#v\n", rss.Modules)
+	}
+	if len(rss.Extensions) != 1 || rss.Extensions[0].XMLName.Local != "unknown" {
+		t.Fatalf("Unexpected channel extensions %#v\n", rss.Extensions)
+	}
+	item, ok := rss.Items[0].Modules[testModuleNamespace].(*testModuleItem)
+	if !ok || item.Rating != 5 || len(item.Tags) != 2 || item.Tags[1] != "b" ||
+		item.Region.Code != "us-or" || item.Region.Name != "Oregon" || len(rss.Items[0].Extensions) != 0 {
+		t.Fatalf("Unexpected item modules %#v\n", rss.Items[0].Modules)
+	}
+	if rss.Items[1].Modules != nil {
+		t.Fatalf("Unexpected item modules %#v\n", rss.Items[1].Modules)
+	}
+
+	// The feed's prefix is kept
+	data, err := xml.Marshal(rss)
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) marshalling the document\n", err)
+	}
+	for _, text := range []string{
+		`<rss version="2.0" xmlns:m="http://example.com/module">`,
+		`<description>A blog</description><m:owner>Jane</m:owner><m:unknown>kept</m:unknown>`,
+		`<item><title>one</title><m:rating>5</m:rating><m:tag>a</m:tag><m:tag>b</m:tag><m:region code="us-or">Oregon</m:region></item>`,
+	} {
+		if !strings.Contains(string(data), text) {
+			t.Fatalf("Marshalled document is missing %v: %v\n", text, string(data))
+		}
+	}
+
+	// Otherwise the registered prefix is used
+	rss = &Rss{Version: Version, Title: "title", Link: "http://github.com/efarrer/rssgo/", Description: "A blog",
+		Modules: Modules{testModuleNamespace: &testModuleChannel{Owner: "Jane"}},
+		Items:   []Item{{Title: "one", Modules: Modules{testModuleNamespace: &testModuleItem{Rating: 3}}}}}
+	data, err = xml.Marshal(rss)
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) marshalling the document\n", err)
+	}
+	expected := `<rss version="2.0" xmlns:mod="http://example.com/module"><channel><title>title</title>` +
+		`<link>http://github.com/efarrer/rssgo/</link><description>A blog</description><mod:owner>Jane</mod:owner>` +
+		`<item><title>one</title><mod:rating>3</mod:rating></item></channel></rss>`
+	if string(data) != expected {
+		t.Fatalf("Unexpected marshalled document expected:\n%v\ngot:\n%v\n", expected, string(data))
+	}
+	reparsed, err := Parse(bytes.NewReader(data))
+	if err != nil || reparsed.Items[0].Modules[testModuleNamespace].(*testModuleItem).Rating != 3 {
+		t.Fatalf("Unexpected reparsed document (%v) %#v\n", err, reparsed)
+	}
+
+	// Nil modules aren't written
+	nilModules := &Rss{Version: Version, Title: "title", Link: "http://github.com/efarrer/rssgo/", Description: "A blog",
+		Modules: Modules{testModuleNamespace: (*testModuleChannel)(nil)},
+		Items:   []Item{{Title: "one", Modules: Modules{testModuleNamespace: (*testModuleItem)(nil)}}}}
+	data, err = xml.Marshal(nilModules)
+	if err != nil || strings.Contains(string(data), "mod:") {
+		t.Fatalf("Unexpected marshalled nil modules (%v) %v\n", err, string(data))
+	}
+
+	// The streaming encoder writes the modules too
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	if err := enc.WriteHeader(rss); err != nil {
+		t.Fatalf("Unexpected error (%v) writing the header\n", err)
+	}
+	if err := enc.WriteItem(rss.Items[0]); err != nil {
+		t.Fatalf("Unexpected error (%v) writing the item\n", err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Unexpected error (%v) closing the encoder\n", err)
+	}
+	if !strings.Contains(buf.String(), `<mod:owner>Jane</mod:owner>`) ||
+		!strings.Contains(buf.String(), `<mod:rating>3</mod:rating>`) {
+		t.Fatalf("Unexpected encoded document %v\n", buf.String())
+	}
+}
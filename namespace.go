@@ -0,0 +1,285 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// The namespace of the xml prefix, which never needs to be declared
+const xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+
+// The prefixes used for well known namespaces that the feed doesn't declare
+var knownPrefixes = map[string]string{
+	"http://purl.org/rss/1.0/modules/content/":     "content",
+	"http://purl.org/dc/elements/1.1/":             "dc",
+	"http://purl.org/dc/terms/":                    "dcterms",
+	"http://www.itunes.com/dtds/podcast-1.0.dtd":   "itunes",
+	"http://search.yahoo.com/mrss/":                "media",
+	"http://www.w3.org/2005/Atom":                  "atom",
+	"https://podcastindex.org/namespace/1.0":       "podcast",
+	"http://purl.org/rss/1.0/modules/slash/":       "slash",
+	"http://wellformedweb.org/CommentAPI/":         "wfw",
+	"http://purl.org/syndication/thread/1.0":       "thr",
+	"http://www.georss.org/georss":                 "georss",
+	"http://www.w3.org/2003/01/geo/wgs84_pos#":     "geo",
+	"http://www.w3.org/1999/02/22-rdf-syntax-ns#":  "rdf",
+	"http://purl.org/rss/1.0/modules/syndication/": "sy",
+}
+
+// Writes tokens whose names have namespace URIs, as returned by xml.Decoder,
+// using prefixes. xml.Encoder declares a namespace as the default namespace of
+// every element that uses it, which isn't how feeds are written and which
+// many feed readers don't understand.
+//
+// Namespaces are declared on the first element that uses them and are in
+// scope until that element ends. The namespace declaration attributes of the
+// tokens are ignored, use declareAttrs to keep their prefixes.
+type nsWriter struct {
+	e *xml.Encoder
+
+	// The prefixes of the namespaces in scope. The default namespace has an
+	// empty prefix.
+	prefixes map[string]string
+
+	// The prefixes that are in use
+	used map[string]bool
+
+	// The namespaces declared by each open element
+	scopes [][]string
+
+	// The namespaces to declare on the next start element
+	pending []string
+
+	// The start of the CDATA element being written, and its text so far
+	cdata *xml.StartElement
+	text  []byte
+}
+
+// The elements whose text is written as CDATA
+var cdataElements = map[xml.Name]bool{
+	{Space: ContentNamespace, Local: "encoded"}: true,
+}
+
+func newNSWriter(e *xml.Encoder) *nsWriter {
+	return &nsWriter{
+		e:        e,
+		prefixes: map[string]string{},
+		used:     map[string]bool{"xml": true, "xmlns": true},
+	}
+}
+
+// Declares the namespace with the prefix on the next start element, unless
+// it's already in scope. An empty prefix declares the default namespace.
+func (w *nsWriter) declare(uri, prefix string) {
+	if _, ok := w.prefixes[uri]; ok || uri == "" || uri == xmlNamespace {
+		return
+	}
+	if w.used[prefix] {
+		prefix = w.newPrefix(uri)
+	}
+	w.prefixes[uri] = prefix
+	w.used[prefix] = true
+	w.pending = append(w.pending, uri)
+}
+
+// Declares the namespace on the next start element, unless it's already in
+// scope, using a prefix from knownPrefixes or a generated one.
+func (w *nsWriter) use(uri string) {
+	w.declare(uri, w.newPrefix(uri))
+}
+
+// Declares the namespaces of the namespace declaration attributes.
+func (w *nsWriter) declareAttrs(attrs []xml.Attr) {
+	for _, attr := range attrs {
+		if attr.Name.Space == "xmlns" {
+			w.declare(attr.Value, attr.Name.Local)
+		} else if attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+			w.declare(attr.Value, "")
+		}
+	}
+}
+
+// Declares the namespaces of the start element and its attributes.
+func (w *nsWriter) useNames(start xml.StartElement) {
+	w.use(start.Name.Space)
+	for _, attr := range start.Attr {
+		if !isNamespaceDecl(attr) {
+			w.use(attr.Name.Space)
+		}
+	}
+}
+
+// Returns an unused prefix for the namespace.
+func (w *nsWriter) newPrefix(uri string) string {
+	if prefix, ok := knownPrefix(uri); ok && !w.used[prefix] {
+		return prefix
+	}
+	for i := 1; ; i++ {
+		prefix := fmt.Sprintf("ns%v", i)
+		if !w.used[prefix] {
+			return prefix
+		}
+	}
+}
+
+// Returns the prefix of a well known or registered namespace.
+func knownPrefix(uri string) (string, bool) {
+	moduleLock.RLock()
+	defer moduleLock.RUnlock()
+	prefix, ok := knownPrefixes[uri]
+	return prefix, ok
+}
+
+// Returns the name with its namespace replaced by the namespace's prefix.
+func (w *nsWriter) name(name xml.Name) xml.Name {
+	switch {
+	case name.Space == "":
+		return name
+	case name.Space == xmlNamespace:
+		return xml.Name{Local: "xml:" + name.Local}
+	case w.prefixes[name.Space] == "":
+		return xml.Name{Local: name.Local}
+	}
+	return xml.Name{Local: w.prefixes[name.Space] + ":" + name.Local}
+}
+
+// Writes the token, declaring any namespaces it uses that aren't in scope.
+// Namespace declaration attributes are dropped.
+func (w *nsWriter) EncodeToken(tok xml.Token) error {
+	if w.cdata != nil {
+		start, text := *w.cdata, w.text
+		w.cdata, w.text = nil, nil
+		switch t := tok.(type) {
+		case xml.CharData:
+			w.cdata, w.text = &start, append(text, t...)
+			return nil
+		case xml.EndElement:
+			w.popScope()
+			return w.e.EncodeElement(struct {
+				Text string `xml:",cdata"`
+			}{string(text)}, start)
+		}
+
+		// The element contains more than text, write it as it is
+		if err := w.e.EncodeToken(start); err != nil {
+			return err
+		}
+		if len(text) != 0 {
+			if err := w.e.EncodeToken(xml.CharData(text)); err != nil {
+				return err
+			}
+		}
+	}
+
+	switch t := tok.(type) {
+	case xml.StartElement:
+		w.useNames(t)
+
+		start := xml.StartElement{Name: w.name(t.Name)}
+		for _, attr := range withoutNamespaceDecls(t.Attr) {
+			start.Attr = append(start.Attr, xml.Attr{Name: w.name(attr.Name), Value: attr.Value})
+		}
+		for _, uri := range w.pending {
+			name := xml.Name{Local: "xmlns"}
+			if prefix := w.prefixes[uri]; prefix != "" {
+				name.Local += ":" + prefix
+			}
+			start.Attr = append(start.Attr, xml.Attr{Name: name, Value: uri})
+		}
+		w.scopes = append(w.scopes, w.pending)
+		w.pending = nil
+		if cdataElements[t.Name] {
+			w.cdata = &start
+			return nil
+		}
+		return w.e.EncodeToken(start)
+
+	case xml.EndElement:
+		end := xml.EndElement{Name: w.name(t.Name)}
+		w.popScope()
+		return w.e.EncodeToken(end)
+	}
+	return w.e.EncodeToken(tok)
+}
+
+// Ends the scope of the namespaces declared by the innermost open element.
+func (w *nsWriter) popScope() {
+	if len(w.scopes) == 0 {
+		return
+	}
+	for _, uri := range w.scopes[len(w.scopes)-1] {
+		delete(w.used, w.prefixes[uri])
+		delete(w.prefixes, uri)
+	}
+	w.scopes = w.scopes[:len(w.scopes)-1]
+}
+
+// The Rss fields without the custom marshalling
+type plainRss Rss
+
+// Writes the <rss> element. The namespaces used by extension elements and
+// attributes are declared on the <rss> element, with the prefixes from the
+// declarations in Attrs where there are any.
+func (rss Rss) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	plain := plainRss(rss)
+	plain.Attrs = withoutNamespaceDecls(rss.Attrs)
+	tokens, err := marshalTokens(plain)
+	if err != nil {
+		return err
+	}
+
+	// The channel>... tags can't set the attributes of <channel>
+	if len(rss.ChannelAttrs) != 0 {
+		for i, tok := range tokens {
+			if t, ok := tok.(xml.StartElement); ok && t.Name.Local == "channel" {
+				t.Attr = append(t.Attr, withoutNamespaceDecls(rss.ChannelAttrs)...)
+				tokens[i] = t
+				break
+			}
+		}
+	}
+
+	w := newNSWriter(e)
+	w.declareAttrs(rss.Attrs)
+	for _, tok := range tokens {
+		if t, ok := tok.(xml.StartElement); ok {
+			w.useNames(t)
+		}
+	}
+
+	for _, tok := range tokens {
+		if err := w.EncodeToken(tok); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Writes the fields of the extension struct v as elements, without an
+// enclosing element. Used by the MarshalXML methods of the extension structs,
+// such as ITunesChannel.
+func marshalFlat(e *xml.Encoder, v interface{}) error {
+	tokens, err := marshalTokens(v)
+	if err != nil {
+		return err
+	}
+	// Nothing is marshalled for a nil pointer
+	if len(tokens) < 2 {
+		return nil
+	}
+
+	for _, tok := range tokens[1 : len(tokens)-1] {
+		if start, ok := tok.(xml.StartElement); ok {
+			start.Attr = withoutNamespaceDecls(start.Attr)
+			tok = start
+		}
+		if err := e.EncodeToken(tok); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Options that control how a feed is parsed
+type ParseOptions struct {
+	// If true the parsed feed is checked with rssgo.Verify before it's
+	// returned
+	Verify bool
+}
+
+// Parses an RSS 2.0 document into a Rss object. Documents encoded as UTF-8,
+// US-ASCII, ISO-8859-1, ISO-8859-15 and windows-1252 are supported, a leading
+// UTF-8 byte order mark is ignored. RSS 0.91 and 0.92 documents are parsed
+// too, see rssgo.Upgrade.
+func Parse(r io.Reader) (*Rss, error) {
+	return ParseWithOptions(r, ParseOptions{})
+}
+
+// Parses an RSS 2.0 document into a Rss object using the provided options.
+func ParseWithOptions(r io.Reader, opts ParseOptions) (*Rss, error) {
+	rss := &Rss{}
+	d := newXMLDecoder(r)
+	start, err := readRoot(d)
+	if err == nil {
+		err = d.DecodeElement(rss, &start)
+	}
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Unable to parse the RSS document (%v)", err))
+	}
+
+	if opts.Verify {
+		if err := Verify(rss); err != nil {
+			return nil, err
+		}
+	}
+
+	return rss, nil
+}
+
+// Creates a xml.Decoder that skips a UTF-8 byte order mark and understands
+// the common legacy character sets.
+func newXMLDecoder(r io.Reader) *xml.Decoder {
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(3); err == nil && string(bom) == "\xef\xbb\xbf" {
+		br.Discard(3)
+	}
+
+	d := xml.NewDecoder(br)
+	d.CharsetReader = charsetReader
+	return d
+}
+
+// Returns the document's root element. If the document has a DOCTYPE, such
+// as the one RSS 0.91 documents use, the HTML character entities are
+// defined.
+func readRoot(d *xml.Decoder) (xml.StartElement, error) {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return xml.StartElement{}, err
+		}
+		switch t := tok.(type) {
+		case xml.Directive:
+			if strings.HasPrefix(string(t), "DOCTYPE") {
+				d.Entity = xml.HTMLEntity
+			}
+		case xml.StartElement:
+			return t.Copy(), nil
+		}
+	}
+}
+
+// Returns a reader that converts input from the named character set to UTF-8.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(charset)) {
+	case "utf-8", "utf8":
+		return input, nil
+	case "us-ascii", "ascii", "iso-8859-1", "iso8859-1", "iso_8859-1",
+		"latin1", "l1", "cp819", "ibm819":
+		return &charmapReader{r: input, table: &latin1Table}, nil
+	case "iso-8859-15", "iso8859-15", "iso_8859-15", "latin9", "latin-9":
+		return &charmapReader{r: input, table: &latin9Table}, nil
+	case "windows-1252", "cp1252", "x-cp1252":
+		return &charmapReader{r: input, table: &windows1252Table}, nil
+	}
+	return nil, errors.New(fmt.Sprintf("Unsupported character set %v", charset))
+}
+
+// Converts a single byte character set to UTF-8.
+type charmapReader struct {
+	r       io.Reader
+	table   *[256]rune
+	pending []byte
+	err     error
+}
+
+func (c *charmapReader) Read(p []byte) (int, error) {
+	for len(c.pending) == 0 {
+		if c.err != nil {
+			return 0, c.err
+		}
+		src := make([]byte, len(p)/utf8.UTFMax+1)
+		n, err := c.r.Read(src)
+		c.err = err
+		for _, b := range src[:n] {
+			var buf [utf8.UTFMax]byte
+			size := utf8.EncodeRune(buf[:], c.table[b])
+			c.pending = append(c.pending, buf[:size]...)
+		}
+	}
+
+	n := copy(p, c.pending)
+	c.pending = c.pending[n:]
+	return n, nil
+}
+
+var latin1Table [256]rune
+var latin9Table [256]rune
+var windows1252Table [256]rune
+
+func init() {
+	for i := 0; i != 256; i++ {
+		latin1Table[i] = rune(i)
+	}
+
+	latin9Table = latin1Table
+	latin9Table[0xA4] = '€'
+	latin9Table[0xA6] = 'Š'
+	latin9Table[0xA8] = 'š'
+	latin9Table[0xB4] = 'Ž'
+	latin9Table[0xB8] = 'ž'
+	latin9Table[0xBC] = 'Œ'
+	latin9Table[0xBD] = 'œ'
+	latin9Table[0xBE] = 'Ÿ'
+
+	windows1252Table = latin1Table
+	copy(windows1252Table[0x80:0xA0], []rune{
+		'€', '\u0081', '‚', 'ƒ', '„', '…', '†', '‡',
+		'ˆ', '‰', 'Š', '‹', 'Œ', '\u008D', 'Ž', '\u008F',
+		'\u0090', '‘', '’', '“', '”', '•', '–', '—',
+		'˜', '™', 'š', '›', 'œ', '\u009D', 'ž', 'Ÿ'})
+}
@@ -0,0 +1,66 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+
+	parseShouldPass := func(doc string, opts ParseOptions, testing string) *Rss {
+		rss, err := ParseWithOptions(strings.NewReader(doc), opts)
+		if err != nil {
+			t.Fatalf("Parse should pass: %v %v\n", testing, err)
+		}
+		return rss
+	}
+	parseShouldFail := func(doc string, opts ParseOptions, reason string) {
+		if _, err := ParseWithOptions(strings.NewReader(doc), opts); err == nil {
+			t.Fatalf("Parse should fail: %v\n", reason)
+		}
+	}
+
+	feed := func(encoding, title string) string {
+		return `<?xml version="1.0" encoding="` + encoding + `"?>
+<rss version="2.0"><channel><title>` + title + `</title>
+<link>http://github.com/efarrer/rssgo/</link><description>A podcast</description>
+<item><title>item</title></item></channel></rss>`
+	}
+
+	rss := parseShouldPass(feed("UTF-8", "café"), ParseOptions{}, "UTF-8")
+	if rss.Title != "café" || len(rss.Items) != 1 || rss.Version != Version {
+		t.Fatalf("Parse returned unexpected values %#v\n", rss)
+	}
+
+	rss = parseShouldPass("\xef\xbb\xbf"+feed("UTF-8", "bom"), ParseOptions{}, "BOM")
+	if rss.Title != "bom" {
+		t.Fatalf("Parse returned unexpected title %v\n", rss.Title)
+	}
+
+	rss = parseShouldPass(feed("ISO-8859-1", "caf\xe9"), ParseOptions{}, "ISO-8859-1")
+	if rss.Title != "café" {
+		t.Fatalf("Parse returned unexpected ISO-8859-1 title %v\n", rss.Title)
+	}
+
+	rss = parseShouldPass(feed("windows-1252", "\x93quoted\x94 \x80"), ParseOptions{}, "windows-1252")
+	if rss.Title != "“quoted” €" {
+		t.Fatalf("Parse returned unexpected windows-1252 title %v\n", rss.Title)
+	}
+
+	rss = parseShouldPass(feed("ISO-8859-15", "\xa4"), ParseOptions{}, "ISO-8859-15")
+	if rss.Title != "€" {
+		t.Fatalf("Parse returned unexpected ISO-8859-15 title %v\n", rss.Title)
+	}
+
+	parseShouldFail(feed("EBCDIC", "title"), ParseOptions{}, "Unsupported encoding")
+	parseShouldFail("<rss><channel>", ParseOptions{}, "Truncated document")
+	parseShouldFail(`<feed xmlns="http://www.w3.org/2005/Atom"/>`, ParseOptions{}, "Not RSS")
+
+	parseShouldPass(feed("UTF-8", ""), ParseOptions{}, "Invalid feeds parse without Verify")
+	parseShouldFail(feed("UTF-8", ""), ParseOptions{Verify: true}, "Invalid feeds fail with Verify")
+	parseShouldPass(feed("UTF-8", "title"), ParseOptions{Verify: true}, "Valid feeds pass with Verify")
+}
@@ -0,0 +1,245 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// The namespace of the Podcasting 2.0 elements, see
+// https://github.com/Podcastindex-org/podcast-namespace
+const PodcastNamespace = "https://podcastindex.org/namespace/1.0"
+
+// The longest funding message or soundbite title, in characters
+const MaxPodcastText = 128
+
+// The Podcasting 2.0 elements of a channel
+type PodcastChannel struct {
+	// Optional. Whether other platforms may import the feed
+	Locked *PodcastLocked `xml:"https://podcastindex.org/namespace/1.0 locked"`
+
+	// Optional. Where listeners can support the podcast
+	Funding []PodcastFunding `xml:"https://podcastindex.org/namespace/1.0 funding"`
+
+	// Optional. The podcast's globally unique identifier, a UUIDv5 of the
+	// feed's URL
+	Guid string `xml:"https://podcastindex.org/namespace/1.0 guid,omitempty"`
+
+	// Optional. The people involved in the podcast
+	Persons []PodcastPerson `xml:"https://podcastindex.org/namespace/1.0 person"`
+}
+
+// The Podcasting 2.0 elements of an item
+type PodcastItem struct {
+	// Optional. The episode's transcripts and captions
+	Transcripts []PodcastTranscript `xml:"https://podcastindex.org/namespace/1.0 transcript"`
+
+	// Optional. The episode's chapters
+	Chapters *PodcastChapters `xml:"https://podcastindex.org/namespace/1.0 chapters"`
+
+	// Optional. The people involved in the episode
+	Persons []PodcastPerson `xml:"https://podcastindex.org/namespace/1.0 person"`
+
+	// Optional. Short clips that promote the episode
+	Soundbites []PodcastSoundbite `xml:"https://podcastindex.org/namespace/1.0 soundbite"`
+}
+
+// Whether other platforms may import the feed
+type PodcastLocked struct {
+	// Required. "yes" if the feed may not be imported, otherwise "no"
+	Locked string `xml:",chardata"`
+
+	// Optional. The email address of the owner, who can unlock the feed
+	Owner string `xml:"owner,attr,omitempty"`
+}
+
+// Where listeners can support the podcast
+type PodcastFunding struct {
+	// Required. The URL of the funding page
+	Url string `xml:"url,attr"`
+
+	// Optional. The link's text, up to rssgo.MaxPodcastText characters
+	Message string `xml:",chardata"`
+}
+
+// A person involved in a podcast or episode
+type PodcastPerson struct {
+	// Required. The person's name
+	Name string `xml:",chardata"`
+
+	// Optional. Their role from the Podcast Taxonomy Project, defaults to
+	// "host"
+	Role string `xml:"role,attr,omitempty"`
+
+	// Optional. Their role's group, defaults to "cast"
+	Group string `xml:"group,attr,omitempty"`
+
+	// Optional. The URL of a picture of the person
+	Img string `xml:"img,attr,omitempty"`
+
+	// Optional. The URL of a page about the person
+	Href string `xml:"href,attr,omitempty"`
+}
+
+// An episode transcript or closed captions file
+type PodcastTranscript struct {
+	// Required. The URL of the file
+	Url string `xml:"url,attr"`
+
+	// Required. The file's MIME type, for example text/vtt
+	Type string `xml:"type,attr"`
+
+	// Optional. The transcript's language, defaults to Rss.Language
+	Language string `xml:"language,attr,omitempty"`
+
+	// Optional. "captions" if the file is closed captions
+	Rel string `xml:"rel,attr,omitempty"`
+}
+
+// An episode's chapters file
+type PodcastChapters struct {
+	// Required. The URL of the file
+	Url string `xml:"url,attr"`
+
+	// Required. The file's MIME type, usually application/json+chapters
+	Type string `xml:"type,attr"`
+}
+
+// A short clip that promotes an episode
+type PodcastSoundbite struct {
+	// Required. The start of the clip, in seconds. See
+	// PodcastSoundbite.StartTimeValue
+	StartTime string `xml:"startTime,attr"`
+
+	// Required. The length of the clip, in seconds. See
+	// PodcastSoundbite.DurationValue
+	Duration string `xml:"duration,attr"`
+
+	// Optional. The clip's title, up to rssgo.MaxPodcastText characters
+	Title string `xml:",chardata"`
+}
+
+// Returns the start of the clip in seconds and true, or false if the start time
+// isn't a number.
+func (s *PodcastSoundbite) StartTimeValue() (float64, bool) {
+	return parseDecimal(s.StartTime)
+}
+
+// Returns the length of the clip in seconds and true, or false if the duration
+// isn't a number.
+func (s *PodcastSoundbite) DurationValue() (float64, bool) {
+	return parseDecimal(s.Duration)
+}
+
+// Writes the elements without an enclosing element.
+func (p PodcastChannel) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type plain PodcastChannel
+	return marshalFlat(e, plain(p))
+}
+
+// Writes the elements without an enclosing element.
+func (p PodcastItem) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type plain PodcastItem
+	return marshalFlat(e, plain(p))
+}
+
+// Verifies the channel's Podcasting 2.0 elements.
+func (v *verifier) verifyPodcastChannel(path string, p *PodcastChannel) {
+	if p.Locked != nil && p.Locked.Locked != "yes" && p.Locked.Locked != "no" {
+		v.add(path+".Locked.Locked", CodePodcastValue, "The podcast locked value must be yes or no.")
+	}
+
+	for i := 0; i != len(p.Funding); i++ {
+		field := fmt.Sprintf("%v.Funding[%v]", path, i)
+		v.verifyURL(field+".Url", "podcast funding url", p.Funding[i].Url)
+		if utf8.RuneCountInString(p.Funding[i].Message) > MaxPodcastText {
+			v.add(field+".Message", CodeRange, fmt.Sprintf("The podcast funding message must be at most %v characters.", MaxPodcastText))
+		}
+	}
+
+	if p.Guid != "" && !isUUID(p.Guid) {
+		v.add(path+".Guid", CodePodcastGuid, "The podcast guid must be a UUID.")
+	}
+
+	v.verifyPodcastPersons(path, p.Persons)
+}
+
+// Verifies the item's Podcasting 2.0 elements.
+func (v *verifier) verifyPodcastItem(path string, p *PodcastItem) {
+	for i := 0; i != len(p.Transcripts); i++ {
+		field := fmt.Sprintf("%v.Transcripts[%v]", path, i)
+		v.verifyURL(field+".Url", "podcast transcript url", p.Transcripts[i].Url)
+		if p.Transcripts[i].Type == "" {
+			v.add(field+".Type", CodeRequired, "The podcast transcript type must be set.")
+		}
+	}
+
+	if p.Chapters != nil {
+		v.verifyURL(path+".Chapters.Url", "podcast chapters url", p.Chapters.Url)
+		if p.Chapters.Type == "" {
+			v.add(path+".Chapters.Type", CodeRequired, "The podcast chapters type must be set.")
+		}
+	}
+
+	v.verifyPodcastPersons(path, p.Persons)
+
+	for i := 0; i != len(p.Soundbites); i++ {
+		field := fmt.Sprintf("%v.Soundbites[%v]", path, i)
+		if p.Soundbites[i].StartTime == "" {
+			v.add(field+".StartTime", CodeRequired, "The podcast soundbite start time must be set.")
+		} else if start, ok := p.Soundbites[i].StartTimeValue(); !ok || start < 0 {
+			v.add(field+".StartTime", CodeRange, "The podcast soundbite start time must be a number that isn't negative.")
+		}
+		if p.Soundbites[i].Duration == "" {
+			v.add(field+".Duration", CodeRequired, "The podcast soundbite duration must be set.")
+		} else if duration, ok := p.Soundbites[i].DurationValue(); !ok || duration <= 0 {
+			v.add(field+".Duration", CodeRange, "The podcast soundbite duration must be a number greater than zero.")
+		}
+		if utf8.RuneCountInString(p.Soundbites[i].Title) > MaxPodcastText {
+			v.add(field+".Title", CodeRange, fmt.Sprintf("The podcast soundbite title must be at most %v characters.", MaxPodcastText))
+		}
+	}
+}
+
+// Verifies podcast:person elements.
+func (v *verifier) verifyPodcastPersons(path string, persons []PodcastPerson) {
+	for i := 0; i != len(persons); i++ {
+		field := fmt.Sprintf("%v.Persons[%v]", path, i)
+		if strings.TrimSpace(persons[i].Name) == "" {
+			v.add(field+".Name", CodeRequired, "The podcast person's name must be set.")
+		}
+		if persons[i].Img != "" {
+			v.verifyURL(field+".Img", "podcast person img", persons[i].Img)
+		}
+		if persons[i].Href != "" {
+			v.verifyURL(field+".Href", "podcast person href", persons[i].Href)
+		}
+	}
+}
+
+// Returns true if the value is a UUID, such as
+// "917393e3-1b1e-5cef-ace4-edaa54e1f810".
+func isUUID(value string) bool {
+	if len(value) != 36 {
+		return false
+	}
+	for i := 0; i != len(value); i++ {
+		c := value[i]
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !isDigit(c) && (c|0x20 < 'a' || c|0x20 > 'f') {
+				return false
+			}
+		}
+	}
+	return true
+}
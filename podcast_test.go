@@ -0,0 +1,140 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestPodcast(t *testing.T) {
+
+	doc := `<rss version="2.0" xmlns:podcast="https://podcastindex.org/namespace/1.0"><channel>
+<title>title</title><link>http://github.com/efarrer/rssgo/</link><description>A podcast</description>
+<podcast:locked owner="owner@example.com">yes</podcast:locked>
+<podcast:funding url="http://example.com/donate">Support the show!</podcast:funding>
+<podcast:guid>917393e3-1b1e-5cef-ace4-edaa54e1f810</podcast:guid>
+<podcast:person href="http://example.com/jane" img="http://example.com/jane.jpg">Jane Doe</podcast:person>
+<podcast:medium>podcast</podcast:medium>
+<item><title>one</title>
+<podcast:transcript url="http://example.com/one.vtt" type="text/vtt" language="en" rel="captions"/>
+<podcast:transcript url="http://example.com/one.srt" type="application/srt"/>
+<podcast:chapters url="http://example.com/one.json" type="application/json+chapters"/>
+<podcast:person role="guest" group="cast">John Smith</podcast:person>
+<podcast:soundbite startTime="73.0" duration="60.5">The best part</podcast:soundbite>
+</item>
+</channel></rss>`
+
+	rss, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing the document\n", err)
+	}
+	p := rss.Podcast
+	if p == nil || p.Locked.Locked != "yes" || p.Locked.Owner != "owner@example.com" || len(p.Funding) != 1 ||
+		p.Funding[0].Url != "http://example.com/donate" || p.Funding[0].Message != "Support the show!" ||
+		p.Guid != "917393e3-1b1e-5cef-ace4-edaa54e1f810" || len(p.Persons) != 1 || p.Persons[0].Name != "Jane Doe" {
+		t.Fatalf("Unexpected podcast channel %#v\n", p)
+	}
+	if len(rss.Extensions) != 1 || rss.Extensions[0].XMLName.Local != "medium" {
+		t.Fatalf("Unexpected channel extensions %#v\n", rss.Extensions)
+	}
+	pi := rss.Items[0].Podcast
+	if pi == nil || len(pi.Transcripts) != 2 || pi.Transcripts[0].Rel != "captions" || pi.Transcripts[0].Language != "en" ||
+		pi.Chapters.Type != "application/json+chapters" || pi.Persons[0].Role != "guest" ||
+		pi.Soundbites[0].StartTime != "73.0" || pi.Soundbites[0].Duration != "60.5" || pi.Soundbites[0].Title != "The best part" {
+		t.Fatalf("Unexpected podcast item %#v\n", pi)
+	}
+	if start, ok := pi.Soundbites[0].StartTimeValue(); !ok || start != 73 {
+		t.Fatalf("Unexpected soundbite start time %v %v\n", start, ok)
+	}
+	if duration, ok := pi.Soundbites[0].DurationValue(); !ok || duration != 60.5 {
+		t.Fatalf("Unexpected soundbite duration %v %v\n", duration, ok)
+	}
+	if err := Verify(rss); err != nil {
+		t.Fatalf("Unexpected verify error %v\n", err)
+	}
+
+	data, err := xml.Marshal(rss)
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) marshalling the document\n", err)
+	}
+	for _, text := range []string{
+		`<rss version="2.0" xmlns:podcast="https://podcastindex.org/namespace/1.0">`,
+		`<podcast:locked owner="owner@example.com">yes</podcast:locked>`,
+		`<podcast:funding url="http://example.com/donate">Support the show!</podcast:funding>`,
+		`<podcast:guid>917393e3-1b1e-5cef-ace4-edaa54e1f810</podcast:guid>`,
+		`<podcast:person img="http://example.com/jane.jpg" href="http://example.com/jane">Jane Doe</podcast:person>`,
+		`<podcast:transcript url="http://example.com/one.vtt" type="text/vtt" language="en" rel="captions"></podcast:transcript>`,
+		`<podcast:chapters url="http://example.com/one.json" type="application/json+chapters"></podcast:chapters>`,
+		`<podcast:person role="guest" group="cast">John Smith</podcast:person>`,
+		`<podcast:soundbite startTime="73.0" duration="60.5">The best part</podcast:soundbite>`,
+	} {
+		if !strings.Contains(string(data), text) {
+			t.Fatalf("Marshalled document is missing %v: %v\n", text, string(data))
+		}
+	}
+	reparsed, err := Parse(bytes.NewReader(data))
+	if err != nil || reparsed.Podcast == nil || reparsed.Podcast.Guid != p.Guid ||
+		len(reparsed.Items[0].Podcast.Transcripts) != 2 {
+		t.Fatalf("Unexpected reparsed document (%v) %#v\n", err, reparsed)
+	}
+
+	// Violations
+	rss.Podcast = &PodcastChannel{
+		Locked:  &PodcastLocked{Locked: "true"},
+		Funding: []PodcastFunding{{Url: "donate", Message: strings.Repeat("x", MaxPodcastText+1)}},
+		Guid:    "917393e3-1b1e-5cef-ace4",
+		Persons: []PodcastPerson{{Img: "jane.jpg"}}}
+	rss.Items[0].Podcast = &PodcastItem{
+		Transcripts: []PodcastTranscript{{Url: "http://example.com/one.vtt"}},
+		Chapters:    &PodcastChapters{Type: "application/json+chapters"},
+		Soundbites:  []PodcastSoundbite{{StartTime: "-1"}, {StartTime: "1:00", Duration: "0"}}}
+
+	expected := ValidationErrors{
+		{Field: "Podcast.Locked.Locked", Code: CodePodcastValue},
+		{Field: "Podcast.Funding[0].Url", Code: CodeURLNotAbsolute},
+		{Field: "Podcast.Funding[0].Message", Code: CodeRange},
+		{Field: "Podcast.Guid", Code: CodePodcastGuid},
+		{Field: "Podcast.Persons[0].Name", Code: CodeRequired},
+		{Field: "Podcast.Persons[0].Img", Code: CodeURLNotAbsolute},
+		{Field: "Items[0].Podcast.Transcripts[0].Type", Code: CodeRequired},
+		{Field: "Items[0].Podcast.Chapters.Url", Code: CodeRequired},
+		{Field: "Items[0].Podcast.Soundbites[0].StartTime", Code: CodeRange},
+		{Field: "Items[0].Podcast.Soundbites[0].Duration", Code: CodeRequired},
+		{Field: "Items[0].Podcast.Soundbites[1].StartTime", Code: CodeRange},
+		{Field: "Items[0].Podcast.Soundbites[1].Duration", Code: CodeRange},
+	}
+	errs := VerifyAll(rss)
+	if len(errs) != len(expected) {
+		t.Fatalf("VerifyAll returned %v errors expected %v: %v\n", len(errs), len(expected), errs)
+	}
+	for i := 0; i != len(expected); i++ {
+		if errs[i].Field != expected[i].Field || errs[i].Code != expected[i].Code {
+			t.Fatalf("VerifyAll returned unexpected error expected: %v/%v got: %v/%v\n",
+				expected[i].Field, expected[i].Code, errs[i].Field, errs[i].Code)
+		}
+	}
+}
+
+func TestPodcastSoundbiteNumbers(t *testing.T) {
+
+	rss, err := Parse(strings.NewReader(`<rss version="2.0" xmlns:podcast="https://podcastindex.org/namespace/1.0">
+<channel><title>title</title><link>http://example.com/</link><description>d</description>
+<item><title>one</title><podcast:soundbite startTime="1:00" duration="30">Clip</podcast:soundbite></item>
+</channel></rss>`))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing a bad start time\n", err)
+	}
+	s := rss.Items[0].Podcast.Soundbites[0]
+	if _, ok := s.StartTimeValue(); ok || s.StartTime != "1:00" {
+		t.Fatalf("Unexpected soundbite %#v\n", s)
+	}
+	errs := VerifyAll(rss)
+	if len(errs) != 1 || errs[0].Field != "Items[0].Podcast.Soundbites[0].StartTime" || errs[0].Code != CodeRange {
+		t.Fatalf("Unexpected verify errors %v\n", errs)
+	}
+}
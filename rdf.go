@@ -0,0 +1,253 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The namespace of the RDF elements, see http://www.w3.org/TR/rdf-syntax-grammar/
+const RDFNamespace = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+
+// The namespace of the RSS 1.0 elements, see http://web.resource.org/rss/1.0/spec
+const RSS1Namespace = "http://purl.org/rss/1.0/"
+
+// The namespace of the RSS 0.90 elements
+const RSS090Namespace = "http://my.netscape.com/rdf/simple/0.9/"
+
+// Parses an RSS 1.0, or RSS 0.90, document into a Rss object. The same
+// character sets as rssgo.Parse are supported. The items are in the order of
+// the channel's rdf:Seq, items that aren't in the sequence follow in document
+// order. The Version is set to rssgo.Version.
+//
+// An item's rdf:about is its Guid, which is a permalink if it's the item's
+// link, and its dc:date is its PubDate. The channel's dc:date, dc:rights and
+// dc:language are its PubDate, Copyright and Language. The Dublin Core
+// creators are kept in DC, see Item.EffectiveAuthor. The channel's rdf:about
+// is its self atom:link. Other elements are parsed as they are in an RSS
+// document, children of <rdf:RDF> that aren't mapped to a Rss field are kept
+// in the channel's Extensions.
+func ParseRDF(r io.Reader) (*Rss, error) {
+	d := newXMLDecoder(r)
+	start, err := readRoot(d)
+	if err == nil && (start.Name.Space != RDFNamespace || start.Name.Local != "RDF") {
+		err = errors.New(fmt.Sprintf("Expecting an <rdf:RDF> element but found <%v>", start.Name.Local))
+	}
+	if err == nil {
+		rss := &Rss{Version: Version}
+		if err = readRDF(d, rss); err == nil {
+			return rss, nil
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("Unable to parse the RSS 1.0 document (%v)", err))
+}
+
+// The items of an RSS 1.0 document and their order
+type rdfItems struct {
+	seq   []string
+	items []Item
+	about []string
+}
+
+// Reads the children of <rdf:RDF> up to and including its end element.
+func readRDF(d *xml.Decoder, rss *Rss) error {
+	var items rdfItems
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Space != RSS1Namespace && t.Name.Space != RSS090Namespace:
+				err = readRDFExtension(d, rss, t)
+			case t.Name.Local == "channel":
+				err = readRDFChannel(d, rss, &items, t)
+			case t.Name.Local == "image":
+				rss.Image = &Image{}
+				err = d.DecodeElement(rss.Image, &t)
+			case t.Name.Local == "textinput":
+				rss.TextInput = &TextInput{}
+				err = d.DecodeElement(rss.TextInput, &t)
+			case t.Name.Local == "item":
+				err = readRDFItem(d, &items, t)
+			default:
+				// Unknown RSS 1.0 elements, such as those of later revisions,
+				// are kept like other namespaces' elements
+				err = readRDFExtension(d, rss, t)
+			}
+			if err != nil {
+				return err
+			}
+		case xml.EndElement:
+			rss.Items = items.ordered()
+			return nil
+		}
+	}
+}
+
+// Reads a child of <rdf:RDF> that isn't mapped to a Rss field into the
+// channel's Extensions.
+func readRDFExtension(d *xml.Decoder, rss *Rss, start xml.StartElement) error {
+	var el Element
+	err := d.DecodeElement(&el, &start)
+	rss.Extensions = append(rss.Extensions, el)
+	return err
+}
+
+// Reads the <channel> element.
+func readRDFChannel(d *xml.Decoder, rss *Rss, items *rdfItems, channel xml.StartElement) error {
+	if about := rdfAttr(channel.Attr, "about"); about != "" {
+		rss.AtomLinks = append(rss.AtomLinks, AtomLink{Href: about, Rel: AtomRelSelf})
+	}
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space != channel.Name.Space {
+				err = decodeChannelElement(d, rss, channel.Name, &t)
+			} else {
+				switch t.Name.Local {
+				case "items":
+					err = items.readSeq(d)
+				case "image", "textinput":
+					// References to the sibling elements
+					err = d.Skip()
+				default:
+					err = decodeChannelElement(d, rss, channel.Name, &t)
+				}
+			}
+			if err != nil {
+				return err
+			}
+		case xml.EndElement:
+			if rss.DC != nil {
+				if rss.PubDate.IsZero() && !rss.DC.Date.Time.IsZero() {
+					rss.PubDate = NewRssTime(rss.DC.Date.Time)
+				}
+				if rss.Copyright == "" {
+					rss.Copyright = rss.DC.Rights
+				}
+			}
+			rss.Language = rdfLanguage(rss)
+			return nil
+		}
+	}
+}
+
+// Returns the channel's language, or its dc:language which is kept in
+// Extensions.
+func rdfLanguage(rss *Rss) string {
+	if rss.Language != "" {
+		return rss.Language
+	}
+	for _, el := range rss.Extensions {
+		if el.XMLName.Space == DublinCoreNamespace && el.XMLName.Local == "language" {
+			return strings.ToLower(el.Text())
+		}
+	}
+	return ""
+}
+
+// Reads the resources of the channel's <items><rdf:Seq> element.
+func (items *rdfItems) readSeq(d *xml.Decoder) error {
+	depth := 1
+	for depth != 0 {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			if t.Name.Space == RDFNamespace && t.Name.Local == "li" {
+				resource := rdfAttr(t.Attr, "resource")
+				if resource == "" {
+					resource = rdfAttr(t.Attr, "about")
+				}
+				items.seq = append(items.seq, resource)
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+	return nil
+}
+
+// Reads an <item> element.
+func readRDFItem(d *xml.Decoder, items *rdfItems, start xml.StartElement) error {
+	item := Item{}
+	about := rdfAttr(start.Attr, "about")
+	for _, attr := range withoutNamespaceDecls(start.Attr) {
+		if attr.Name.Local != "about" || (attr.Name.Space != RDFNamespace && attr.Name.Space != "") {
+			item.Attrs = append(item.Attrs, attr)
+		}
+	}
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if err := decodeItemElement(d, &item, start.Name, &t); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			if about != "" && item.Guid == nil {
+				item.Guid = &Guid{Guid: about, IsPermaLink: about == item.Link}
+			}
+			if item.DC != nil && item.PubDate.IsZero() && !item.DC.Date.Time.IsZero() {
+				item.PubDate = NewRssTime(item.DC.Date.Time)
+			}
+			items.items = append(items.items, item)
+			items.about = append(items.about, about)
+			return nil
+		}
+	}
+}
+
+// Returns the items in the order of the sequence, followed by the items that
+// aren't in the sequence.
+func (items *rdfItems) ordered() []Item {
+	var ordered []Item
+	used := make([]bool, len(items.items))
+	for _, resource := range items.seq {
+		for i := 0; i != len(items.items); i++ {
+			if !used[i] && resource != "" && items.about[i] == resource {
+				used[i] = true
+				ordered = append(ordered, items.items[i])
+				break
+			}
+		}
+	}
+	for i := 0; i != len(items.items); i++ {
+		if !used[i] {
+			ordered = append(ordered, items.items[i])
+		}
+	}
+	return ordered
+}
+
+// Returns the value of an rdf: attribute. Attributes without a namespace are
+// accepted too as many feeds leave the prefix off.
+func rdfAttr(attrs []xml.Attr, local string) string {
+	for _, attr := range attrs {
+		if attr.Name.Local == local && (attr.Name.Space == RDFNamespace || attr.Name.Space == "") {
+			return attr.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,124 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRDF(t *testing.T) {
+
+	doc := `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/"
+  xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:sy="http://purl.org/rss/1.0/modules/syndication/">
+<channel rdf:about="http://www.xml.com/xml/news.rss">
+<title>XML.com</title>
+<link>http://xml.com/pub</link>
+<description>XML.com features a rich mix of information and services for the XML community.</description>
+<dc:language>en-US</dc:language>
+<dc:rights>Copyright 2000</dc:rights>
+<dc:date>2000-01-01T12:00:00+00:00</dc:date>
+<dc:creator>Jane Doe</dc:creator>
+<sy:updatePeriod>hourly</sy:updatePeriod>
+<image rdf:resource="http://xml.com/universal/images/xml_tiny.gif"/>
+<items>
+<rdf:Seq>
+<rdf:li resource="http://xml.com/pub/2000/08/09/rdfdb/index.html"/>
+<rdf:li rdf:resource="http://xml.com/pub/2000/08/09/xslt/xslt.html"/>
+<rdf:li rdf:resource="http://xml.com/pub/missing.html"/>
+</rdf:Seq>
+</items>
+<textinput rdf:resource="http://search.xml.com"/>
+</channel>
+<image rdf:about="http://xml.com/universal/images/xml_tiny.gif">
+<title>XML.com</title><link>http://www.xml.com</link><url>http://xml.com/universal/images/xml_tiny.gif</url>
+</image>
+<item rdf:about="http://xml.com/pub/2000/08/09/xslt/xslt.html">
+<title>Processing Inclusions with XSLT</title>
+<link>http://xml.com/pub/2000/08/09/xslt/xslt.html</link>
+<description>Processing document inclusions with general XML tools can be problematic.</description>
+<dc:date>2000-08-09T10:00:00-05:00</dc:date>
+<dc:creator>Bob DuCharme</dc:creator>
+</item>
+<item rdf:about="urn:example:unlisted"><title>Unlisted</title><link>http://xml.com/unlisted</link></item>
+<item rdf:about="http://xml.com/pub/2000/08/09/rdfdb/index.html">
+<title>Putting RDF to Work</title>
+<link>http://xml.com/pub/2000/08/09/rdfdb/index.html</link>
+<description>Tool and API support for the Resource Description Framework is slowly coming of age.</description>
+<dc:creator>Edd Dumbill</dc:creator>
+<dc:creator>Dan Brickley</dc:creator>
+</item>
+<textinput rdf:about="http://search.xml.com">
+<title>Search XML.com</title><description>Search XML.com's XML collection</description>
+<name>s</name><link>http://search.xml.com</link>
+</textinput>
+<rating>(PICS-1.1)</rating>
+</rdf:RDF>`
+
+	rss, err := ParseRDF(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing the document\n", err)
+	}
+	if rss.Version != Version || rss.Title != "XML.com" || rss.Link != "http://xml.com/pub" ||
+		rss.Language != "en-us" || rss.Copyright != "Copyright 2000" || rss.DC.Creators[0] != "Jane Doe" ||
+		!rss.PubDate.Time.Equal(time.Date(2000, time.January, 1, 12, 0, 0, 0, time.UTC)) ||
+		rss.AtomLink(AtomRelSelf).Href != "http://www.xml.com/xml/news.rss" {
+		t.Fatalf("Unexpected channel %#v\n", rss)
+	}
+	if rss.Image.Url != "http://xml.com/universal/images/xml_tiny.gif" || rss.Image.Link != "http://www.xml.com" ||
+		rss.TextInput.Name != "s" || rss.TextInput.Link != "http://search.xml.com" {
+		t.Fatalf("Unexpected image or text input %#v %#v\n", rss.Image, rss.TextInput)
+	}
+	if len(rss.Extensions) != 3 || rss.Extensions[1].XMLName.Local != "updatePeriod" ||
+		rss.Extensions[2].XMLName != (xml.Name{Space: RSS1Namespace, Local: "rating"}) ||
+		rss.Extensions[2].Text() != "(PICS-1.1)" {
+		t.Fatalf("Unexpected extensions %#v\n", rss.Extensions)
+	}
+
+	if len(rss.Items) != 3 || rss.Items[0].Title != "Putting RDF to Work" ||
+		rss.Items[1].Title != "Processing Inclusions with XSLT" || rss.Items[2].Title != "Unlisted" {
+		t.Fatalf("Unexpected items %#v\n", rss.Items)
+	}
+	item := rss.Items[0]
+	if item.Guid.Guid != "http://xml.com/pub/2000/08/09/rdfdb/index.html" || !item.Guid.IsPermaLink ||
+		item.EffectiveAuthor() != "Edd Dumbill, Dan Brickley" || !item.PubDate.IsZero() || len(item.Attrs) != 0 {
+		t.Fatalf("Unexpected item %#v\n", item)
+	}
+	item = rss.Items[1]
+	if item.EffectiveAuthor() != "Bob DuCharme" ||
+		!item.PubDate.Time.Equal(time.Date(2000, time.August, 9, 15, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Unexpected item %#v\n", item)
+	}
+	if rss.Items[2].Guid.Guid != "urn:example:unlisted" || rss.Items[2].Guid.IsPermaLink {
+		t.Fatalf("Unexpected item %#v\n", rss.Items[2])
+	}
+	if err := Verify(rss); err != nil {
+		t.Fatalf("Unexpected verify error %v\n", err)
+	}
+
+	// RSS 0.90 has no sequence
+	rss, err = ParseRDF(strings.NewReader(`<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" ` +
+		`xmlns="http://my.netscape.com/rdf/simple/0.9/"><channel><title>Mozilla Dot Org</title>` +
+		`<link>http://www.mozilla.org</link><description>the Mozilla Organization web site</description></channel>` +
+		`<item><title>New Status Updates</title><link>http://www.mozilla.org/status/</link></item>` +
+		`<item><title>Bugzilla Reorganized</title><link>http://www.mozilla.org/bugs/</link></item></rdf:RDF>`))
+	if err != nil || rss.Title != "Mozilla Dot Org" || len(rss.Items) != 2 ||
+		rss.Items[1].Link != "http://www.mozilla.org/bugs/" || rss.Items[0].Guid != nil {
+		t.Fatalf("Unexpected RSS 0.90 document (%v) %#v\n", err, rss)
+	}
+
+	for _, bad := range []string{
+		`<rss version="2.0"><channel></channel></rss>`,
+		`<RDF><channel></channel></RDF>`,
+		`<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"><channel>`,
+	} {
+		if _, err := ParseRDF(strings.NewReader(bad)); err == nil {
+			t.Fatalf("Expected an error parsing %v\n", bad)
+		}
+	}
+}
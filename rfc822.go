@@ -0,0 +1,304 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// The errors returned by scanRssDate. They're created once so that failing to
+// parse a date doesn't allocate either.
+var (
+	errDateWeekday  = errors.New("Bad RFC822 date. Expecting a weekday followed by a comma")
+	errDateDay      = errors.New("Bad RFC822 date. Expecting a one or two digit day")
+	errDateMonth    = errors.New("Bad RFC822 date. Expecting a three letter month")
+	errDateYear     = errors.New("Bad RFC822 date. Expecting a two or four digit year")
+	errDateTime     = errors.New("Bad RFC822 date. Expecting a time of hh:mm or hh:mm:ss")
+	errDateZone     = errors.New("Bad RFC822 date. Expecting a time zone name or +hhmm/-hhmm offset")
+	errDateRange    = errors.New("Bad RFC822 date. A date or time value is out of range")
+	errDateTrailing = errors.New("Bad RFC822 date. Unexpected text after the time zone")
+)
+
+// The optional parts of an RFC822 date that scanRssDate found
+type dateFields struct {
+	weekday       bool
+	oneDigitDay   bool
+	fourDigitYear bool
+	seconds       bool
+	numericZone   bool
+}
+
+// Returns the time.Parse layout that matches the scanned date.
+func (f dateFields) layout() string {
+	format := ""
+	if f.weekday {
+		format += dayPrefix
+	}
+	if f.oneDigitDay {
+		format += oneDigitDayMonth
+	} else {
+		format += dayMonth
+	}
+	if f.fourDigitYear {
+		format += fourYear
+	} else {
+		format += twoYear
+	}
+	if f.seconds {
+		format += includeSeconds
+	} else {
+		format += excludeSeconds
+	}
+	if f.numericZone {
+		format += localDifferential
+	} else {
+		format += zone
+	}
+	return format
+}
+
+var shortMonthNames = [...]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun",
+	"Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
+var shortDayNames = [...]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+
+// Parses an RFC822/RFC1123 date with a 2 or 4 character year without
+// allocating. The grammar is:
+//
+//	[weekday ","] day month year hh ":" mm [":" ss [fraction]] zone
+//
+// Fields may be separated by more than one space. Named time zones are
+// resolved to their offsets. Unknown names are left to time.Parse, which
+// allocates, and are treated as offset zero unless they're used by the local
+// time zone.
+func scanRssDate(s string) (time.Time, dateFields, error) {
+	var f dateFields
+	i := 0
+
+	if n := letters(s, i); n != 0 && i+n < len(s) && s[i+n] == ',' {
+		if n != 3 || lookupName(shortDayNames[:], s[i:i+n]) == -1 {
+			return time.Time{}, f, errDateWeekday
+		}
+		f.weekday = true
+		if i = spaces(s, i+n+1); i == -1 {
+			return time.Time{}, f, errDateWeekday
+		}
+	}
+
+	day, n := number(s, i, 2)
+	if n == 0 {
+		return time.Time{}, f, errDateDay
+	}
+	f.oneDigitDay = n == 1
+	if i = spaces(s, i+n); i == -1 {
+		return time.Time{}, f, errDateDay
+	}
+
+	if letters(s, i) != 3 {
+		return time.Time{}, f, errDateMonth
+	}
+	month := lookupName(shortMonthNames[:], s[i:i+3]) + 1
+	if month == 0 {
+		return time.Time{}, f, errDateMonth
+	}
+	if i = spaces(s, i+3); i == -1 {
+		return time.Time{}, f, errDateMonth
+	}
+
+	year, n := number(s, i, 4)
+	switch n {
+	case 2:
+		// The same pivot as time.Parse
+		if year >= 69 {
+			year += 1900
+		} else {
+			year += 2000
+		}
+	case 4:
+		f.fourDigitYear = true
+	default:
+		return time.Time{}, f, errDateYear
+	}
+	if i = spaces(s, i+n); i == -1 {
+		return time.Time{}, f, errDateYear
+	}
+
+	hour, n := number(s, i, 2)
+	if n == 0 || i+n >= len(s) || s[i+n] != ':' {
+		return time.Time{}, f, errDateTime
+	}
+	i += n + 1
+	minute, n := number(s, i, 2)
+	if n != 2 {
+		return time.Time{}, f, errDateTime
+	}
+	i += n
+	second, nanosecond := 0, 0
+	if i < len(s) && s[i] == ':' {
+		f.seconds = true
+		if second, n = number(s, i+1, 2); n != 2 {
+			return time.Time{}, f, errDateTime
+		}
+		i += n + 1
+
+		// Fractional seconds, as accepted by time.Parse
+		if i+1 < len(s) && (s[i] == '.' || s[i] == ',') && isDigit(s[i+1]) {
+			i++
+			for scale := 100000000; i < len(s) && isDigit(s[i]); i++ {
+				nanosecond += int(s[i]-'0') * scale
+				scale /= 10
+			}
+		}
+	}
+	if i = spaces(s, i); i == -1 {
+		return time.Time{}, f, errDateTime
+	}
+
+	if day < 1 || day > daysIn(time.Month(month), year) || hour > 23 ||
+		minute > 59 || second > 59 {
+		return time.Time{}, f, errDateRange
+	}
+
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		f.numericZone = true
+		hh, n := number(s, i+1, 2)
+		if n != 2 {
+			return time.Time{}, f, errDateZone
+		}
+		mm, n := number(s, i+3, 2)
+		if n != 2 {
+			return time.Time{}, f, errDateZone
+		}
+		if hh > 23 || mm > 59 {
+			return time.Time{}, f, errDateRange
+		}
+		offset := (hh*60 + mm) * 60
+		if s[i] == '-' {
+			offset = -offset
+		}
+		i += 5
+		if i != len(s) {
+			return time.Time{}, f, errDateTrailing
+		}
+
+		t := time.Date(year, time.Month(month), day, hour, minute, second,
+			nanosecond, time.UTC).Add(-time.Duration(offset) * time.Second)
+		if _, localOffset := t.In(time.Local).Zone(); localOffset == offset {
+			return t.In(time.Local), f, nil
+		}
+		return t.In(offsetLocation(offset)), f, nil
+	}
+
+	n = 0
+	for i+n < len(s) && s[i+n] >= 'A' && s[i+n] <= 'Z' {
+		n++
+	}
+	if n == 0 || n > 5 {
+		return time.Time{}, f, errDateZone
+	}
+	if i+n != len(s) {
+		return time.Time{}, f, errDateTrailing
+	}
+
+	loc, ok := zoneLocations[s[i:]]
+	if !ok {
+		if n < 3 {
+			return time.Time{}, f, errDateZone
+		}
+
+		// Only time.Parse knows every name used by the local time zone
+		t, err := time.Parse(f.layout(), s)
+		return t, f, err
+	}
+	return time.Date(year, time.Month(month), day, hour, minute, second,
+		nanosecond, loc), f, nil
+}
+
+// Returns the number of ASCII letters starting at s[i].
+func letters(s string, i int) int {
+	n := 0
+	for i+n < len(s) && (s[i+n]|0x20 >= 'a' && s[i+n]|0x20 <= 'z') {
+		n++
+	}
+	return n
+}
+
+// Returns the index after the spaces starting at s[i], or -1 if there are
+// none.
+func spaces(s string, i int) int {
+	if i >= len(s) || s[i] != ' ' {
+		return -1
+	}
+	for i < len(s) && s[i] == ' ' {
+		i++
+	}
+	return i
+}
+
+// Returns the value of up to max digits starting at s[i] and the number of
+// digits.
+func number(s string, i, max int) (int, int) {
+	value, n := 0, 0
+	for n < max && i+n < len(s) && isDigit(s[i+n]) {
+		value = value*10 + int(s[i+n]-'0')
+		n++
+	}
+	return value, n
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// Returns the index of the name that matches value ignoring case, or -1.
+func lookupName(names []string, value string) int {
+	for i, name := range names {
+		if len(name) != len(value) {
+			continue
+		}
+		match := true
+		for j := 0; j != len(name); j++ {
+			if name[j]|0x20 != value[j]|0x20 {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+// Returns the number of days in the month.
+func daysIn(month time.Month, year int) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// Locations for numeric offsets are created once and reused so that parsing
+// doesn't allocate
+var locationCache = struct {
+	sync.RWMutex
+	offsets map[int]*time.Location
+}{offsets: map[int]*time.Location{}}
+
+// Returns a location with the offset, in seconds, and no name.
+func offsetLocation(offset int) *time.Location {
+	locationCache.RLock()
+	loc, ok := locationCache.offsets[offset]
+	locationCache.RUnlock()
+	if ok {
+		return loc
+	}
+
+	locationCache.Lock()
+	defer locationCache.Unlock()
+	if loc, ok = locationCache.offsets[offset]; !ok {
+		loc = time.FixedZone("", offset)
+		locationCache.offsets[offset] = loc
+	}
+	return loc
+}
@@ -0,0 +1,164 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The original regular expression based implementation of ParseRssDate, kept
+// to check compatibility and for benchmarking.
+func regexpParseRssDate(date string) (time.Time, error) {
+
+	format := ""
+
+	// See if it has a leading day
+	if strings.Contains(date, ",") {
+		format += dayPrefix
+	}
+
+	format += dayMonth
+
+	// See if it has a 4 character year
+	if matched, _ := regexp.Match(".*[A-Z][a-z]{2} [0-9]{4}", []byte(date)); matched {
+		format += fourYear
+	} else {
+		format += twoYear
+	}
+
+	if 2 == strings.Count(date, ":") {
+		format += includeSeconds
+	} else {
+		format += excludeSeconds
+	}
+
+	if strings.Contains(date, "+") || strings.Contains(date, "-") {
+		format += localDifferential
+	} else {
+		format += zone
+	}
+
+	return time.Parse(format, date)
+}
+
+var rfc822Dates = []string{
+	"23 Jul 74 09:10 UTC",
+	"23 Jul 1974 09:10 UTC",
+	"Tue, 23 Jul 74 09:10 UTC",
+	"Tue, 23 Jul 1974 09:10 UTC",
+	"Tue, 23 Jul 1974 09:10:30 UTC",
+	"Tue, 23 Jul 1974 09:10:30 GMT",
+	"Tue, 23 Jul 1974 09:10:30 EDT",
+	"Tue, 23 Jul 1974 09:10:30 PST",
+	"Tue, 23 Jul 1974 09:10:30 +0700",
+	"Tue, 23 Jul 1974 09:10:30 -0330",
+	"Tue, 23 Jul 1974 09:10:30 +0000",
+	"Tue, 23 Jul 1974 09:10:30.250 +0000",
+	"Tue, 23 jul 74 09:10:30 +0000",
+	"Sun, 29 Feb 2004 9:10 GMT",
+	"Tue,  23  Jul  1974  09:10:30  GMT",
+	"tue, 23 Jul 1974 09:10:30 GMT",
+	"01 Jan 00 00:00 GMT",
+	"31 Dec 68 23:59:59 GMT",
+}
+
+// Dates with zone names that time.Parse handles
+var unknownZoneRfc822Dates = []string{
+	"Tue, 23 Jul 1974 09:10:30 CEST",
+	"Tue, 23 Jul 1974 09:10:30 XYZ",
+}
+
+var invalidRfc822Dates = []string{
+	"",
+	"Some time tomorrow",
+	"Tue 23 Jul 1974 09:10:30 GMT",
+	"Tue,23 Jul 1974 09:10:30 GMT",
+	"Tuesday, 23 Jul 1974 09:10:30 GMT",
+	"Xyz, 23 Jul 1974 09:10:30 GMT",
+	"23 July 1974 09:10:30 GMT",
+	"23 Jul 974 09:10:30 GMT",
+	"23 Jul 1974 09:10:30",
+	"23 Jul 1974 09:10:30 GMT junk",
+	"23 Jul 1974 09:10:30 GMT ",
+	" 23 Jul 1974 09:10:30 GMT",
+	"23 Jul 1974 09:1 GMT",
+	"23 Jul 1974 09:10:3 GMT",
+	"23 Jul 1974 24:10:30 GMT",
+	"23 Jul 1974 09:60:30 GMT",
+	"23 Jul 1974 09:10:60 GMT",
+	"32 Jul 1974 09:10:30 GMT",
+	"29 Feb 1974 09:10:30 GMT",
+	"00 Jul 1974 09:10:30 GMT",
+	"23 Jul 1974 09:10:30 +07",
+	"23 Jul 1974 09:10:30 +0760",
+	"23 Jul 1974 09:10:30 utc",
+	"23 Jul 1974 09:10:30 ABCDEF",
+}
+
+func TestScanRssDate(t *testing.T) {
+
+	for _, date := range append(rfc822Dates, unknownZoneRfc822Dates...) {
+		actual, err := ParseRssDate(date)
+		if err != nil {
+			t.Fatalf("Unexpected error (%v) when parsing %v\n", err, date)
+		}
+
+		// The regular expression implementation can't parse every date but
+		// those it does parse must match
+		expected, err := regexpParseRssDate(date)
+		if err != nil {
+			continue
+		}
+		expected = resolveZone(expected)
+		if !actual.Equal(expected) {
+			t.Fatalf("Unexpected time.Time when parsing %v. Expected: %v got: %v\n",
+				date, expected, actual)
+		}
+		actualName, actualOffset := actual.Zone()
+		expectedName, expectedOffset := expected.Zone()
+		if actualName != expectedName || actualOffset != expectedOffset {
+			t.Fatalf("Unexpected zone when parsing %v. Expected: %v %v got: %v %v\n",
+				date, expectedName, expectedOffset, actualName, actualOffset)
+		}
+
+		_, layout, _ := ParseDate(date, DateStrict)
+		if _, err := time.Parse(layout, date); err != nil {
+			t.Fatalf("Layout %v doesn't match %v (%v)\n", layout, date, err)
+		}
+	}
+
+	for _, date := range invalidRfc822Dates {
+		if _, err := ParseRssDate(date); err == nil {
+			t.Fatalf("Parsing %v should fail\n", date)
+		}
+	}
+
+	for _, date := range rfc822Dates {
+		date := date
+		allocs := testing.AllocsPerRun(100, func() {
+			ParseRssDate(date)
+		})
+		if allocs != 0 {
+			t.Fatalf("Parsing %v allocated %v times\n", date, allocs)
+		}
+	}
+}
+
+func BenchmarkParseRssDate(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ParseRssDate(rfc822Dates[i%len(rfc822Dates)])
+	}
+}
+
+func BenchmarkParseRssDateRegexp(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		regexpParseRssDate(rfc822Dates[i%len(rfc822Dates)])
+	}
+}
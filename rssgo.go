@@ -4,15 +4,16 @@
 
 /* Package rssgo provides a basic interface for processing RSS version 2.0 feeds
    as defined by http://cyber.law.harvard.edu/rss/rss.html
+
+   Values that feeds often get wrong, such as dates and the numbers in
+   extension elements, are kept as their text so that a feed with a bad value
+   can still be parsed. The typed value is read with the field's accessor,
+   such as ITunesItem.EpisodeNumber, and rssgo.Verify reports bad values.
 */
 package rssgo
 
 import (
-	"errors"
-	"fmt"
-	"net/url"
-	"regexp"
-	"strings"
+	"encoding/xml"
 	"time"
 )
 
@@ -39,6 +40,15 @@ type Rss struct {
 	// Required. Value should be rssgo.Version.
 	Version string `xml:"version,attr"`
 
+	// Optional. The other attributes of the <rss> element, including its
+	// namespace declarations
+	Attrs []xml.Attr `xml:",any,attr"`
+
+	// Optional. The attributes of the <channel> element. Unknown attributes
+	// of the other elements that rssgo supports, such as <enclosure> and
+	// <guid>, aren't kept.
+	ChannelAttrs []xml.Attr `xml:"-"`
+
 	// Required. The title of your channel.
 	Title string `xml:"channel>title"`
 
@@ -61,13 +71,13 @@ type Rss struct {
 	// Optional. Email address for the channel's web master
 	WebMaster string `xml:"channel>webMaster,omitempty"`
 
-	// Optional. Publication date of the channel. See rssgo.ComposeRssDate and
-	// rssgo.ParseRssDate
-	PubDate string `xml:"channel>pubDate,omitempty"`
+	// Optional. Publication date of the channel. See rssgo.NewRssTime and
+	// rssgo.RssTimeFromString
+	PubDate RssTime `xml:"channel>pubDate"`
 
 	// Optional. Date of last change to the channel content. See
-	// rssgo.ComposeRssDate and rssgo.ParseRssDate
-	LastBuildDate string `xml:"channel>lastBuildDate,omitempty"`
+	// rssgo.NewRssTime and rssgo.RssTimeFromString
+	LastBuildDate RssTime `xml:"channel>lastBuildDate"`
 
 	// Optional. The hierarchical categorizations.
 	Categories []Category `xml:"channel>category"`
@@ -100,6 +110,34 @@ type Rss struct {
 	// Optional. The days when aggregators may not read the channel
 	SkipDays *Days `xml:"channel>skipDays,omitempty"`
 
+	// Optional. Links to related resources, such as the feed itself or a
+	// WebSub hub. See Rss.AtomLink
+	AtomLinks []AtomLink `xml:"http://www.w3.org/2005/Atom channel>link"`
+
+	// Optional. The iTunes podcast elements
+	ITunes *ITunesChannel `xml:"channel>itunes,extension"`
+
+	// Optional. The Dublin Core elements
+	DC *DublinCore `xml:"channel>dc,extension"`
+
+	// Optional. The Podcasting 2.0 elements
+	Podcast *PodcastChannel `xml:"channel>podcast,extension"`
+
+	// Optional. The GeoRSS and W3C geo elements
+	Geo *GeoLocation `xml:"channel>geo,extension"`
+
+	// Optional. The Atom elements that have no RSS equivalent
+	Atom *AtomChannel `xml:"channel>atom,extension"`
+
+	// Optional. The elements of the registered modules. See
+	// rssgo.RegisterModule
+	Modules Modules `xml:"channel>modules,omitempty"`
+
+	// Optional. The child elements of <channel> that rssgo doesn't support,
+	// such as elements from extension namespaces. They're written before the
+	// items, even if they came after them in the parsed document.
+	Extensions []Element `xml:"channel>extension"`
+
 	// Optional. The RSS feed's items
 	Items []Item `xml:"channel>item"`
 }
@@ -130,12 +168,58 @@ type Item struct {
 	// Optional. A unique identifier for the item
 	Guid *Guid `xml:"guid"`
 
-	// Optional. Publication date of the item. See rssgo.ComposeRssDate and
-	// rssgo.ParseRssDate
-	PubDate string `xml:"pubDate,omitempty"`
+	// Optional. Publication date of the item. See rssgo.NewRssTime and
+	// rssgo.RssTimeFromString
+	PubDate RssTime `xml:"pubDate"`
 
 	// Optional. The RSS channel the item came from.
 	Source *Source `xml:"source"`
+
+	// Optional. The item's full content, usually HTML, from the RSS content
+	// module's <content:encoded> element. Written as CDATA. See Item.Body
+	Content string `xml:"http://purl.org/rss/1.0/modules/content/ encoded,omitempty"`
+
+	// Optional. The text of the Slash module's <slash:comments> element,
+	// the number of comments on the item. See Item.CommentCount
+	SlashComments string `xml:"http://purl.org/rss/1.0/modules/slash/ comments,omitempty"`
+
+	// Optional. The URL of the feed of the item's comments, from the
+	// <wfw:commentRss> element
+	CommentRss string `xml:"http://wellformedweb.org/CommentAPI/ commentRss,omitempty"`
+
+	// Optional. The resources the item is a response to, from the Atom
+	// threading extension's <thr:in-reply-to> elements
+	InReplyTo []InReplyTo `xml:"http://purl.org/syndication/thread/1.0 in-reply-to"`
+
+	// Optional. The iTunes podcast elements
+	ITunes *ITunesItem `xml:"itunes,extension"`
+
+	// Optional. The Dublin Core elements. See Item.EffectiveAuthor and
+	// Item.EffectivePubDate
+	DC *DublinCore `xml:"dc,extension"`
+
+	// Optional. The Media RSS elements. See Item.Renditions
+	Media *MediaItem `xml:"media,extension"`
+
+	// Optional. The Podcasting 2.0 elements
+	Podcast *PodcastItem `xml:"podcast,extension"`
+
+	// Optional. The GeoRSS and W3C geo elements. See Item.Location
+	Geo *GeoLocation `xml:"geo,extension"`
+
+	// Optional. The Atom elements that have no RSS equivalent
+	Atom *AtomItem `xml:"atom,extension"`
+
+	// Optional. The elements of the registered modules. See
+	// rssgo.RegisterModule
+	Modules Modules `xml:"modules,omitempty"`
+
+	// Optional. The attributes of the <item> element
+	Attrs []xml.Attr `xml:",any,attr"`
+
+	// Optional. The child elements that rssgo doesn't support, such as
+	// elements from extension namespaces
+	Extensions []Element `xml:",any"`
 }
 
 // The RSS channel the item came from.
@@ -245,209 +329,9 @@ type Category struct {
 	Domain string `xml:"domain,attr,omitempty"`
 }
 
-// Verifies that the contents of the Rss object will conform to the RSS 2.0
-// spec.
-func Verify(r *Rss) error {
-
-	if r.Version != Version {
-		return errors.New(fmt.Sprintf("Bad version. Expecting %v", Version))
-	}
-
-	if r.Title == "" {
-		return errors.New("Empty title. The title must be set")
-	}
-
-	_, err := url.Parse(r.Link)
-	if err != nil {
-		return errors.New(fmt.Sprintf("Bad channel link. Expecting a valid URL (%v)", err))
-	}
-
-	if r.Description == "" {
-		return errors.New("Empty description. The description must be set")
-	}
-
-	if r.Language != "" && !allowableLanguageMap[r.Language] {
-		return errors.New(`Invalid language. Allowable language values are found 
-at http://cyber.law.harvard.edu/rss/languages.html`)
-	}
-
-	// Verify the validity of field dates
-	verifyDateFields := func(field string) error {
-		if field != "" {
-			_, err := ParseRssDate(field)
-			if err != nil {
-				return err
-			}
-		}
-		return nil
-	}
-
-	if err := verifyDateFields(r.PubDate); err != nil {
-		return errors.New(fmt.Sprintf("Unable to parse the RSS PubDate (%v)", err))
-	}
-
-	if err := verifyDateFields(r.LastBuildDate); err != nil {
-		return errors.New(fmt.Sprintf("Unable to parse the RSS LastBuildDate (%v)", err))
-	}
-
-	for i := 0; i != len(r.Categories); i++ {
-		if r.Categories[i].Category == "" {
-			return errors.New("Category should not be empty.")
-		}
-	}
-
-	if r.Docs != "" && r.Docs != DocsURL {
-		return errors.New(fmt.Sprintf("Docs should be empty or %v", DocsURL))
-	}
-
-	if r.Cloud != nil {
-		if r.Cloud.Domain == "" {
-			return errors.New("Cloud domain must not be empty")
-		}
-		if err != nil || r.Cloud.Port < 1 || r.Cloud.Port > 65535 {
-			return errors.New("Cloud port must be from 1 to 65535.")
-		}
-		if r.Cloud.Path == "" || r.Cloud.Path[0] != '/' {
-			return errors.New("Invalid cloud path.")
-		}
-		if r.Cloud.RegisterProcedure == "" {
-			return errors.New("Invalid cloud register procedure.")
-		}
-		if !allowableCloudProtocolMap[r.Cloud.Protocol] {
-			return errors.New("Invalid cloud protocol. It must be xml-rpc, soap, or http-post")
-		}
-	}
-
-	if r.Ttl < 0 {
-		return errors.New("Ttl field must be a positive integer.")
-	}
-
-	if r.Image != nil {
-		_, err := url.Parse(r.Image.Url)
-		if err != nil {
-			return errors.New(fmt.Sprintf("Bad image url. Expecting a valid URL (%v)", err))
-		}
-
-		if r.Image.Title == "" {
-			return errors.New("Empty image title. The image title must be set")
-		}
-
-		_, err = url.Parse(r.Image.Link)
-		if err != nil {
-			return errors.New(fmt.Sprintf("Bad image link. Expecting a valid URL (%v)", err))
-		}
-
-		if r.Image.Width < 0 || r.Image.Width > 144 {
-			return errors.New("Image width must be from 1 to 144.")
-		}
-
-		if r.Image.Height < 0 || r.Image.Height > 400 {
-			return errors.New("Image heigth must be from 1 to 400.")
-		}
-	}
-
-	if r.TextInput != nil {
-		if r.TextInput.Title == "" {
-			return errors.New("Text input's title must be set.")
-		}
-
-		if r.TextInput.Description == "" {
-			return errors.New("Text input's description must be set.")
-		}
-
-		if r.TextInput.Name == "" {
-			return errors.New("Text input's name must be set.")
-		}
-
-		_, err := url.Parse(r.TextInput.Link)
-		if err != nil {
-			return errors.New(fmt.Sprintf("Bad text input's link. Expecting a valid URL (%v)", err))
-		}
-	}
-
-	if r.SkipHours != nil {
-		for h := 0; h != len(r.SkipHours.Hours); h++ {
-			hour := r.SkipHours.Hours[h]
-			if err != nil || hour < 0 || hour > 23 {
-				return errors.New("The skipHour's hour must be from 0 to 23")
-			}
-		}
-	}
-
-	if r.SkipDays != nil {
-		for d := 0; d != len(r.SkipDays.Days); d++ {
-			if !allowableSkipDays[r.SkipDays.Days[d]] {
-				return errors.New("Invalid skip day. Allowable skip days can be found at http://cyber.law.harvard.edu/rss/skipHoursDays.html#skiphours")
-			}
-		}
-	}
-
-	for i := 0; i != len(r.Items); i++ {
-		if r.Items[i].Title == "" {
-			if r.Items[i].Description == "" {
-				return errors.New("The item title or description must be set.")
-			}
-		}
-
-		if r.Items[i].Link != "" {
-			_, err := url.Parse(r.Items[i].Link)
-			if err != nil {
-				return errors.New(fmt.Sprintf("Bad item link. Expecting a valid URL (%v)", err))
-			}
-		}
-
-		if r.Items[i].Comments != "" {
-			_, err := url.Parse(r.Items[i].Comments)
-			if err != nil {
-				return errors.New(fmt.Sprintf("Bad item comments. Expecting a valid URL (%v)", err))
-			}
-		}
-
-		if r.Items[i].Enclosure != nil {
-			_, err := url.Parse(r.Items[i].Enclosure.Url)
-			if err != nil {
-				return errors.New(fmt.Sprintf("Bad item enclosure url. Expecting a valid URL (%v)", err))
-			}
-
-			if r.Items[i].Enclosure.Length <= 0 {
-				return errors.New("The item enclosure length should not be greater than zero.")
-			}
-
-			if r.Items[i].Enclosure.Type == "" {
-				return errors.New("The item enclosure type must be set.")
-			}
-		}
-
-		if r.Items[i].Guid != nil {
-			if r.Items[i].Guid.IsPermaLink {
-				_, err := url.Parse(r.Items[i].Guid.Guid)
-				if err != nil {
-					return errors.New(fmt.Sprintf("Bad item guid body. Expecting a valid URL (%v)", err))
-				}
-			}
-		}
-
-		if err := verifyDateFields(r.Items[i].PubDate); err != nil {
-			return errors.New(fmt.Sprintf("Unable to parse the item PubDate (%v)", err))
-		}
-
-		if r.Items[i].Source != nil {
-			if r.Items[i].Source.Source == "" {
-				return errors.New("The item source must be set.")
-			}
-
-			_, err := url.Parse(r.Items[i].Source.Url)
-			if err != nil {
-				return errors.New(fmt.Sprintf("Bad item source url. Expecting a valid URL (%v)", err))
-			}
-		}
-	}
-
-	return nil
-}
-
 const dayPrefix = "Mon, "
 const dayMonth = "02 Jan "
+const oneDigitDayMonth = "2 Jan "
 const fourYear = "2006 "
 const twoYear = "06 "
 const includeSeconds = "15:04:05 "
@@ -458,44 +342,18 @@ const rfc822WithFourCharacterYear = "02 Jan 2006 15:04 MST"
 
 /*
  Parses a date/time string that matches the RSS 2.0 format (RFC822 with 2 or 4
- character year) into a time.Time type.
+ character year) into a time.Time type. Named time zones are resolved to their
+ offsets. See rssgo.ParseDate for parsing real-world dates.
 */
 func ParseRssDate(date string) (time.Time, error) {
-
-	format := ""
-
-	// See if it has a leading day
-	if strings.Contains(date, ",") {
-		format += dayPrefix
-	}
-
-	format += dayMonth
-
-	// See if it has a 4 character year
-	if matched, _ := regexp.Match(".*[A-Z][a-z]{2} [0-9]{4}", []byte(date)); matched {
-		format += fourYear
-	} else {
-		format += twoYear
-	}
-
-	if 2 == strings.Count(date, ":") {
-		format += includeSeconds
-	} else {
-		format += excludeSeconds
-	}
-
-	if strings.Contains(date, "+") || strings.Contains(date, "-") {
-		format += localDifferential
-	} else {
-		format += zone
-	}
-
-	return time.Parse(format, date)
+	t, _, err := scanRssDate(date)
+	return t, err
 }
 
 /*
  Compose a date/time string that matches the RSS 2.0 format (RFC822 with 4
- character year) into a time.Time type.
+ character year) into a time.Time type. See rssgo.ComposeDate for other
+ formats, such as the recommended rssgo.DefaultDateFormat.
 */
 func ComposeRssDate(date time.Time) string {
 	return date.Format(rfc822WithFourCharacterYear)
@@ -140,28 +140,28 @@ func TestVerify(t *testing.T) {
 
 	// PubDate
 	rss = createValidRss()
-	rss.PubDate = ""
+	rss.PubDate = RssTime{}
 	verifyShouldPass(rss, "PubDate can be empty")
 
 	rss = createValidRss()
-	rss.PubDate = ComposeRssDate(time.Now())
+	rss.PubDate = RssTimeFromString(ComposeRssDate(time.Now()))
 	verifyShouldPass(rss, "Valid PubDate")
 
 	rss = createValidRss()
-	rss.PubDate = "Some time tomorrow"
+	rss.PubDate = RssTimeFromString("Some time tomorrow")
 	verifyShouldFail(rss, "Invalid PubDate")
 
 	// LastBuildDate
 	rss = createValidRss()
-	rss.LastBuildDate = ""
+	rss.LastBuildDate = RssTime{}
 	verifyShouldPass(rss, "LastBuildDate can be empty")
 
 	rss = createValidRss()
-	rss.LastBuildDate = ComposeRssDate(time.Now())
+	rss.LastBuildDate = RssTimeFromString(ComposeRssDate(time.Now()))
 	verifyShouldPass(rss, "Valid LastBuildDate")
 
 	rss = createValidRss()
-	rss.LastBuildDate = "Some time tomorrow"
+	rss.LastBuildDate = RssTimeFromString("Some time tomorrow")
 	verifyShouldFail(rss, "Invalid LastBuildDate")
 
 	// Categories
@@ -417,12 +417,12 @@ func TestVerify(t *testing.T) {
 	verifyShouldPass(rss, "Items days can be empty")
 
 	createValidItems := func() []Item {
-		return []Item{{"title", "http://link.com", "the item", "author@authors.com",
-			[]Category{{"categories", ""}}, "http://comments.com", nil, nil,
-			"23 Jul 74 09:10 UTC", nil},
-			{"title2", "http://link2.com", "the 2 item", "author2@authors.com",
-				[]Category{}, "http://comments2.com", nil, nil, "23 Jul 74 08:10 UTC",
-				nil}}
+		return []Item{{Title: "title", Link: "http://link.com", Description: "the item",
+			Author: "author@authors.com", Categories: []Category{{"categories", ""}},
+			Comments: "http://comments.com", PubDate: RssTimeFromString("23 Jul 74 09:10 UTC")},
+			{Title: "title2", Link: "http://link2.com", Description: "the 2 item",
+				Author: "author2@authors.com", Categories: []Category{},
+				Comments: "http://comments2.com", PubDate: RssTimeFromString("23 Jul 74 08:10 UTC")}}
 	}
 
 	rss = createValidRss()
@@ -538,17 +538,17 @@ func TestVerify(t *testing.T) {
 	// Item.PubDate
 	rss = createValidRss()
 	rss.Items = createValidItems()
-	rss.Items[0].PubDate = ""
+	rss.Items[0].PubDate = RssTime{}
 	verifyShouldPass(rss, "PubDate can be empty")
 
 	rss = createValidRss()
 	rss.Items = createValidItems()
-	rss.Items[0].PubDate = ComposeRssDate(time.Now())
+	rss.Items[0].PubDate = RssTimeFromString(ComposeRssDate(time.Now()))
 	verifyShouldPass(rss, "Valid PubDate")
 
 	rss = createValidRss()
 	rss.Items = createValidItems()
-	rss.Items[0].PubDate = "Some time tomorrow"
+	rss.Items[0].PubDate = RssTimeFromString("Some time tomorrow")
 	verifyShouldFail(rss, "Invalid PubDate")
 
 	// Source
@@ -603,8 +603,8 @@ func TestSerialize(t *testing.T) {
 		Copyright:      "coptyright 20032",
 		ManagingEditor: "managing.editor@gmail.com (Managing Editor)",
 		WebMaster:      "web.master@gmail.com (Web Master)",
-		PubDate:        ComposeRssDate(time.Now()),
-		LastBuildDate:  ComposeRssDate(time.Now()),
+		PubDate:        NewRssTime(time.Now()),
+		LastBuildDate:  NewRssTime(time.Now()),
 		Categories: []Category{
 			{Category: "Some category"},
 			{Category: "Other category", Domain: "http://domain.com"}},
@@ -645,7 +645,7 @@ func TestSerialize(t *testing.T) {
 					Type:   "mpeg/audio"},
 				Guid: &Guid{
 					Guid: "http://guid.com", IsPermaLink: true},
-				PubDate: ComposeRssDate(time.Now()),
+				PubDate: NewRssTime(time.Now()),
 				Source: &Source{
 					Source: "thetitle",
 					Url:    "http://www.foo.com"}}}}
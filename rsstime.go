@@ -0,0 +1,177 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+)
+
+// A date stored in an RSS element such as <pubDate>. The element's original
+// text is kept so that parsed dates are written back unchanged.
+type RssTime struct {
+	// The element's text, without leading and trailing white space. It's
+	// written unless Time has been changed since the text was parsed, then
+	// Time is written using rssgo.DefaultDateFormat.
+	Text string
+
+	// The parsed date. When Text is set by parsing a document this is the
+	// zero time.Time if Text couldn't be parsed, even leniently.
+	Time time.Time
+
+	// The Time that Text was parsed as, so that a changed Time can be found
+	// without parsing Text again
+	parsed time.Time
+}
+
+// Creates a RssTime for a date. The date is written using
+// rssgo.DefaultDateFormat.
+func NewRssTime(date time.Time) RssTime {
+	return RssTime{Time: date}
+}
+
+// Creates a RssTime from a date/time string, which is parsed with
+// rssgo.ParseDate in lenient mode. Use this to set a date from a string as was
+// done before the date fields were typed.
+func RssTimeFromString(text string) RssTime {
+	t := RssTime{Text: strings.TrimSpace(text)}
+	t.Time, _, _ = ParseDate(t.Text, DateLenient)
+	t.parsed = t.Time
+	return t
+}
+
+// Returns true if neither the text or the date are set.
+func (t RssTime) IsZero() bool {
+	return t.Text == "" && t.Time.IsZero()
+}
+
+// Returns the text that's written for the date, this is the same as the old
+// string fields. The Text is returned unless Time has been changed since it
+// was parsed.
+func (t RssTime) String() string {
+	if !t.changed() {
+		return t.Text
+	}
+	return ComposeDate(t.Time, DefaultDateFormat())
+}
+
+// Returns true if Time is set and isn't the instant that Text was parsed as.
+func (t RssTime) changed() bool {
+	return !t.Time.IsZero() && (t.Text == "" || !t.Time.Equal(t.parsed))
+}
+
+// Writes the date's text, nothing is written for a zero RssTime.
+func (t RssTime) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if t.IsZero() {
+		return nil
+	}
+	return e.EncodeElement(t.String(), start)
+}
+
+// Reads the date's text and parses it with rssgo.ParseDate in lenient mode. A
+// date that can't be parsed isn't an error, rssgo.Verify reports it.
+func (t *RssTime) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var text string
+	if err := d.DecodeElement(&text, &start); err != nil {
+		return err
+	}
+	*t = RssTimeFromString(text)
+	return nil
+}
+
+// The layouts of the W3C date and time formats, see
+// http://www.w3.org/TR/NOTE-datetime
+var w3cLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// Parses a W3C date and time (a profile of ISO 8601), as used by dc:date and
+// Atom. For example "2006-01-02T15:04:05Z" or "2006-01-02".
+func ParseW3CDate(date string) (time.Time, error) {
+	var err error
+	for _, layout := range w3cLayouts {
+		var t time.Time
+		if t, err = time.Parse(layout, date); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// A date stored in an element that uses the W3C date and time format, such
+// as <dc:date>. Like RssTime the element's original text is kept.
+type W3CTime struct {
+	// The element's text, without leading and trailing white space. It's
+	// written unless Time has been changed since the text was parsed, then
+	// Time is written using time.RFC3339.
+	Text string
+
+	// The parsed date. This is the zero time.Time if Text couldn't be parsed,
+	// even leniently.
+	Time time.Time
+
+	// The Time that Text was parsed as, see RssTime
+	parsed time.Time
+}
+
+// Creates a W3CTime for a date. The date is written using time.RFC3339.
+func NewW3CTime(date time.Time) W3CTime {
+	return W3CTime{Time: date}
+}
+
+// Creates a W3CTime from a date/time string. If the string isn't a W3C date
+// it's parsed with rssgo.ParseDate in lenient mode.
+func W3CTimeFromString(text string) W3CTime {
+	t := W3CTime{Text: strings.TrimSpace(text)}
+	var err error
+	if t.Time, err = ParseW3CDate(t.Text); err != nil {
+		t.Time, _, _ = ParseDate(t.Text, DateLenient)
+	}
+	t.parsed = t.Time
+	return t
+}
+
+// Returns true if neither the text or the date are set.
+func (t W3CTime) IsZero() bool {
+	return t.Text == "" && t.Time.IsZero()
+}
+
+// Returns the text that's written for the date. The Text is returned unless
+// Time has been changed since it was parsed.
+func (t W3CTime) String() string {
+	if !t.changed() {
+		return t.Text
+	}
+	return t.Time.Format(time.RFC3339)
+}
+
+// Returns true if Time is set and isn't the instant that Text was parsed as.
+func (t W3CTime) changed() bool {
+	return !t.Time.IsZero() && (t.Text == "" || !t.Time.Equal(t.parsed))
+}
+
+// Writes the date's text, nothing is written for a zero W3CTime.
+func (t W3CTime) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if t.IsZero() {
+		return nil
+	}
+	return e.EncodeElement(t.String(), start)
+}
+
+// Reads the date's text, see rssgo.W3CTimeFromString. A date that can't be
+// parsed isn't an error, rssgo.Verify reports it.
+func (t *W3CTime) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var text string
+	if err := d.DecodeElement(&text, &start); err != nil {
+		return err
+	}
+	*t = W3CTimeFromString(text)
+	return nil
+}
@@ -0,0 +1,178 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRssTime(t *testing.T) {
+
+	doc := `<rss version="2.0"><channel><title>title</title>
+<link>http://github.com/efarrer/rssgo/</link><description>A podcast</description>
+<pubDate>Tuesday, 23 July 1974 09:10:11 GMT</pubDate>
+<lastBuildDate>Some time tomorrow</lastBuildDate>
+<item><title>item</title><pubDate>Tue, 23 Jul 1974 09:10:11 -0700</pubDate></item>
+<item><title>no date</title></item>
+</channel></rss>`
+
+	rss, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing the document\n", err)
+	}
+
+	expected := time.Date(1974, time.July, 23, 9, 10, 11, 0, time.UTC)
+	if rss.PubDate.Text != "Tuesday, 23 July 1974 09:10:11 GMT" || !rss.PubDate.Time.Equal(expected) {
+		t.Fatalf("Unexpected channel PubDate %#v\n", rss.PubDate)
+	}
+	if rss.LastBuildDate.Text != "Some time tomorrow" || !rss.LastBuildDate.Time.IsZero() {
+		t.Fatalf("Unexpected channel LastBuildDate %#v\n", rss.LastBuildDate)
+	}
+	if !rss.Items[0].PubDate.Time.Equal(expected.Add(7 * time.Hour)) {
+		t.Fatalf("Unexpected item PubDate %#v\n", rss.Items[0].PubDate)
+	}
+	if !rss.Items[1].PubDate.IsZero() {
+		t.Fatalf("Missing item PubDate should be zero %#v\n", rss.Items[1].PubDate)
+	}
+
+	// Verify reports dates that aren't RFC822
+	errs := VerifyAll(rss)
+	if len(errs) != 2 || errs[0].Field != "PubDate" || errs[1].Field != "LastBuildDate" {
+		t.Fatalf("Unexpected verify errors %v\n", errs)
+	}
+
+	// The original text is written back
+	data, err := xml.Marshal(rss)
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) marshalling the document\n", err)
+	}
+	for _, text := range []string{
+		"<pubDate>Tuesday, 23 July 1974 09:10:11 GMT</pubDate>",
+		"<lastBuildDate>Some time tomorrow</lastBuildDate>",
+		"<pubDate>Tue, 23 Jul 1974 09:10:11 -0700</pubDate>",
+	} {
+		if !strings.Contains(string(data), text) {
+			t.Fatalf("Marshalled document is missing %v: %v\n", text, string(data))
+		}
+	}
+	if strings.Count(string(data), "<pubDate>") != 2 {
+		t.Fatalf("Zero dates should not be written: %v\n", string(data))
+	}
+
+	// Dates without text are composed
+	rss.PubDate = NewRssTime(expected)
+	rss.LastBuildDate = RssTime{}
+	data, err = xml.Marshal(rss)
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) marshalling the document\n", err)
+	}
+	if !strings.Contains(string(data), "<pubDate>Tue, 23 Jul 1974 09:10:11 +0000</pubDate>") ||
+		strings.Contains(string(data), "lastBuildDate") {
+		t.Fatalf("Unexpected marshalled dates: %v\n", string(data))
+	}
+
+	if rss.PubDate.String() != "Tue, 23 Jul 1974 09:10:11 +0000" {
+		t.Fatalf("Unexpected String() %v\n", rss.PubDate.String())
+	}
+	if s := RssTimeFromString("23 Jul 74 09:10 UTC").String(); s != "23 Jul 74 09:10 UTC" {
+		t.Fatalf("Unexpected String() %v\n", s)
+	}
+	if (RssTime{}).String() != "" {
+		t.Fatalf("Zero RssTime should be an empty string\n")
+	}
+
+	// A changed Time replaces the parsed text
+	changed := RssTimeFromString("Mon, 02 Jan 2006 15:04:05 GMT")
+	changed.Time = expected
+	data, err = xml.Marshal(struct {
+		XMLName xml.Name `xml:"item"`
+		PubDate RssTime  `xml:"pubDate"`
+	}{PubDate: changed})
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) marshalling the date\n", err)
+	}
+	if string(data) != "<item><pubDate>Tue, 23 Jul 1974 09:10:11 +0000</pubDate></item>" {
+		t.Fatalf("Unexpected marshalled changed date: %v\n", string(data))
+	}
+
+	// The text is trimmed so Verify checks what's written
+	padded := RssTimeFromString("  Tue, 23 Jul 1974 09:10:11 GMT\n")
+	if padded.Text != "Tue, 23 Jul 1974 09:10:11 GMT" || verifyDateField(padded) != nil {
+		t.Fatalf("Unexpected padded date %#v\n", padded)
+	}
+	if verifyDateField(RssTime{Text: " Tue, 23 Jul 1974 09:10:11 GMT"}) == nil {
+		t.Fatalf("Expected an error verifying a padded date\n")
+	}
+
+	// A date with an unknown time zone isn't parsed as UTC
+	unknown := RssTimeFromString("Tue, 23 Jul 1974 09:10:30 CEST")
+	if !unknown.Time.IsZero() || verifyDateField(unknown) == nil {
+		t.Fatalf("Expected an error verifying a date with an unknown zone %#v\n", unknown)
+	}
+	channel := &Rss{Version: Version, Title: "title", Link: "http://link.com", Description: "description",
+		PubDate: unknown}
+	if errs := VerifyAll(channel); errs == nil || errs[0].Field != "PubDate" {
+		t.Fatalf("Expected Verify to report the unknown zone %v\n", errs)
+	}
+
+	// The text isn't parsed again to write or verify it
+	parsed := RssTimeFromString("23 Jul 74 09:10 UTC")
+	allocs := testing.AllocsPerRun(100, func() {
+		if parsed.String() != parsed.Text || verifyDateField(parsed) != nil {
+			t.Fatalf("Unexpected parsed date %#v\n", parsed)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("Writing and verifying a parsed date allocated %v times\n", allocs)
+	}
+}
+
+func TestW3CTime(t *testing.T) {
+
+	for date, expected := range map[string]time.Time{
+		"1974-07-23T09:10:11Z":       time.Date(1974, time.July, 23, 9, 10, 11, 0, time.UTC),
+		"1974-07-23T09:10:11.5Z":     time.Date(1974, time.July, 23, 9, 10, 11, 500000000, time.UTC),
+		"1974-07-23T02:10:11-07:00":  time.Date(1974, time.July, 23, 9, 10, 11, 0, time.UTC),
+		"1974-07-23T09:10Z":          time.Date(1974, time.July, 23, 9, 10, 0, 0, time.UTC),
+		"1974-07-23":                 time.Date(1974, time.July, 23, 0, 0, 0, 0, time.UTC),
+		"1974-07":                    time.Date(1974, time.July, 1, 0, 0, 0, 0, time.UTC),
+		"1974":                       time.Date(1974, time.January, 1, 0, 0, 0, 0, time.UTC),
+		"1974-07-23T12:40:11+03:30 ": time.Time{},
+	} {
+		d, err := ParseW3CDate(date)
+		if expected.IsZero() {
+			if err == nil {
+				t.Fatalf("Expected an error for %q\n", date)
+			}
+			continue
+		}
+		if err != nil || !d.Equal(expected) {
+			t.Fatalf("Unexpected date for %q expected %v got %v (%v)\n", date, expected, d, err)
+		}
+	}
+
+	// Other dates are parsed leniently
+	expected := time.Date(1974, time.July, 23, 9, 10, 11, 0, time.UTC)
+	if d := W3CTimeFromString("Tue, 23 Jul 1974 09:10:11 GMT"); !d.Time.Equal(expected) {
+		t.Fatalf("Unexpected lenient date %#v\n", d)
+	}
+	if d := NewW3CTime(expected); d.String() != "1974-07-23T09:10:11Z" {
+		t.Fatalf("Unexpected String() %v\n", d.String())
+	}
+	changed := W3CTimeFromString("2006-01-02")
+	if changed.String() != "2006-01-02" {
+		t.Fatalf("Unexpected String() %v\n", changed.String())
+	}
+	changed.Time = expected
+	if changed.String() != "1974-07-23T09:10:11Z" {
+		t.Fatalf("Unexpected changed String() %v\n", changed.String())
+	}
+	if (W3CTime{}).String() != "" || !(W3CTime{}).IsZero() {
+		t.Fatalf("Zero W3CTime should be an empty string\n")
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// The RSS versions that rssgo.Upgrade converts to rssgo.Version
+var legacyVersions = []string{"0.91", "0.92", "0.93", "0.94"}
+
+// Returns the RSS versions that rssgo.Upgrade converts to rssgo.Version.
+func LegacyVersions() []string {
+	return append([]string(nil), legacyVersions...)
+}
+
+// Upgrades an RSS 0.91, 0.92, 0.93 or 0.94 feed, as read by rssgo.Parse, to
+// RSS 2.0. The Version and Docs are rewritten and the values that 2.0
+// requires are synthesized:
+//
+//	Title                  from the image's title
+//	Link                   from the image's link or the first item's link
+//	Description            from the title
+//	Image.Title and Link   from the channel's title and link
+//	an item's Title        from its link, when it has no title or description
+//
+// RSS 0.91 skip hours are from 1 to 24, an hour of 24 is changed to 0.
+// The report lists every value that was changed or synthesized, a value that
+// can't be synthesized is left for rssgo.Verify to report. A 2.0 feed isn't
+// changed and any other version is an error.
+func Upgrade(rss *Rss) (ConversionReport, error) {
+	version := strings.TrimSpace(rss.Version)
+	if version == Version {
+		return nil, nil
+	}
+	if !containsString(legacyVersions, version) {
+		return nil, errors.New(fmt.Sprintf("Unable to upgrade RSS version %#v", rss.Version))
+	}
+
+	var report ConversionReport
+	rss.Version = Version
+	report.add("Version", ConversionChanged, fmt.Sprintf("The version was changed from %v", version))
+
+	if rss.Docs != "" && rss.Docs != DocsURL {
+		report.add("Docs", ConversionChanged, fmt.Sprintf("The docs URL was changed from %v", rss.Docs))
+		rss.Docs = DocsURL
+	}
+
+	if rss.Title == "" && rss.Image != nil && rss.Image.Title != "" {
+		rss.Title = rss.Image.Title
+		report.add("Title", ConversionSynthesized, "The title was copied from the image's title")
+	}
+
+	if rss.Link == "" {
+		if rss.Image != nil && rss.Image.Link != "" {
+			rss.Link = rss.Image.Link
+			report.add("Link", ConversionSynthesized, "The link was copied from the image's link")
+		} else if len(rss.Items) != 0 && rss.Items[0].Link != "" {
+			rss.Link = rss.Items[0].Link
+			report.add("Link", ConversionSynthesized, "The link was copied from the first item's link")
+		}
+	}
+
+	if strings.TrimSpace(rss.Description) == "" && rss.Title != "" {
+		rss.Description = rss.Title
+		report.add("Description", ConversionSynthesized, "The description was copied from the title")
+	}
+
+	if rss.Image != nil {
+		if rss.Image.Title == "" && rss.Title != "" {
+			rss.Image.Title = rss.Title
+			report.add("Image.Title", ConversionSynthesized, "The image's title was copied from the title")
+		}
+		if rss.Image.Link == "" && rss.Link != "" {
+			rss.Image.Link = rss.Link
+			report.add("Image.Link", ConversionSynthesized, "The image's link was copied from the link")
+		}
+	}
+
+	if rss.SkipHours != nil {
+		for i, hour := range rss.SkipHours.Hours {
+			if hour == 24 {
+				rss.SkipHours.Hours[i] = 0
+				report.add(fmt.Sprintf("SkipHours.Hours[%v]", i), ConversionChanged, "The hour 24 was changed to 0")
+			}
+		}
+	}
+
+	for i := 0; i != len(rss.Items); i++ {
+		item := &rss.Items[i]
+		if item.Title == "" && item.Description == "" && item.Link != "" {
+			item.Title = item.Link
+			report.add(fmt.Sprintf("Items[%v].Title", i), ConversionSynthesized, "The item's title was copied from its link")
+		}
+	}
+	return report, nil
+}
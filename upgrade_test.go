@@ -0,0 +1,100 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUpgrade(t *testing.T) {
+
+	doc := "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n" +
+		`<!DOCTYPE rss PUBLIC "-//Netscape Communications//DTD RSS 0.91//EN" "http://my.netscape.com/publish/formats/rss-0.91.dtd">
+<rss version="0.91"><channel>
+<title>Caf&eacute; news</title>
+<link>http://example.com/</link>
+<description></description>
+<language>en-us</language>
+<rating>(PICS-1.1 "http://www.rsac.org/ratingsv01.html" l gen true comment "RSACi North America Server" r (n 0 s 0 v 0 l 0))</rating>
+<docs>http://my.netscape.com/publish/formats/rss-spec-0.91.html</docs>
+<image><url>http://example.com/logo.gif</url><width>88</width><height>31</height></image>
+<textinput><title>Search</title><description>Search the news</description><name>q</name><link>http://example.com/search</link></textinput>
+<skipHours><hour>24</hour><hour>1</hour></skipHours>
+<item><title>First&nbsp;item</title><link>http://example.com/1</link></item>
+<item><link>http://example.com/2</link></item>
+</channel></rss>`
+
+	rss, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing the document\n", err)
+	}
+	if rss.Version != "0.91" || rss.Title != "Café news" || rss.Items[0].Title != "First\u00a0item" ||
+		rss.TextInput == nil || rss.TextInput.Name != "q" || len(rss.Extensions) != 0 || !strings.HasPrefix(rss.Rating, "(PICS-1.1") {
+		t.Fatalf("Unexpected channel %#v\n", rss)
+	}
+	if Verify(rss) == nil {
+		t.Fatalf("Expected an error verifying a 0.91 document\n")
+	}
+
+	report, err := Upgrade(rss)
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) upgrading the document\n", err)
+	}
+	expected := ConversionReport{
+		{Field: "Version", Conversion: ConversionChanged},
+		{Field: "Docs", Conversion: ConversionChanged},
+		{Field: "Description", Conversion: ConversionSynthesized},
+		{Field: "Image.Title", Conversion: ConversionSynthesized},
+		{Field: "Image.Link", Conversion: ConversionSynthesized},
+		{Field: "SkipHours.Hours[0]", Conversion: ConversionChanged},
+		{Field: "Items[1].Title", Conversion: ConversionSynthesized},
+	}
+	if len(report) != len(expected) {
+		t.Fatalf("Upgrade reported %v notes expected %v:\n%v\n", len(report), len(expected), report)
+	}
+	for i := 0; i != len(expected); i++ {
+		if report[i].Field != expected[i].Field || report[i].Conversion != expected[i].Conversion {
+			t.Fatalf("Upgrade reported an unexpected note expected: %v/%v got: %v\n",
+				expected[i].Field, expected[i].Conversion, report[i])
+		}
+	}
+	if rss.Version != Version || rss.Docs != DocsURL || rss.Description != "Café news" ||
+		rss.Image.Title != "Café news" || rss.Image.Link != "http://example.com/" ||
+		rss.SkipHours.Hours[0] != 0 || rss.Items[1].Title != "http://example.com/2" {
+		t.Fatalf("Unexpected upgraded channel %#v\n", rss)
+	}
+	if err := Verify(rss); err != nil {
+		t.Fatalf("Unexpected verify error %v\n", err)
+	}
+
+	// A 0.92 document without a channel link
+	rss, err = Parse(strings.NewReader(`<rss version="0.92"><channel><title>t</title><description>d</description>` +
+		`<item><description>only a description</description><link>http://example.com/1</link>` +
+		`<enclosure url="http://example.com/1.mp3" length="1" type="audio/mpeg"/></item></channel></rss>`))
+	if err != nil {
+		t.Fatalf("Unexpected error (%v) parsing the document\n", err)
+	}
+	report, err = Upgrade(rss)
+	if err != nil || len(report) != 2 || report[1].Field != "Link" || rss.Link != "http://example.com/1" ||
+		rss.Items[0].Title != "" {
+		t.Fatalf("Unexpected upgrade (%v) %v %#v\n", err, report, rss)
+	}
+
+	// 2.0 documents aren't changed, other versions can't be upgraded
+	if report, err := Upgrade(&Rss{Version: Version}); err != nil || len(report) != 0 {
+		t.Fatalf("Unexpected upgrade of a 2.0 document (%v) %v\n", err, report)
+	}
+	if _, err := Upgrade(&Rss{Version: "1.0"}); err == nil {
+		t.Fatalf("Expected an error upgrading a 1.0 document\n")
+	}
+
+	// Changing the returned versions doesn't change what's upgraded
+	versions := LegacyVersions()
+	versions[0] = "1.0"
+	if _, err := Upgrade(&Rss{Version: "1.0"}); err == nil || LegacyVersions()[0] != "0.91" {
+		t.Fatalf("Expected LegacyVersions to return a copy\n")
+	}
+}
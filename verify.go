@@ -0,0 +1,586 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Stable identifiers for the rules checked by rssgo.VerifyAll
+const (
+	// The version isn't rssgo.Version
+	CodeVersion = "version"
+
+	// A required field is empty
+	CodeRequired = "required"
+
+	// A field isn't a valid URL
+	CodeURL = "url"
+
+	// A URL is relative
+	CodeURLNotAbsolute = "url-not-absolute"
+
+	// A URL's scheme isn't one of the allowed schemes
+	CodeURLScheme = "url-scheme"
+
+	// A URL doesn't have a host
+	CodeURLHost = "url-host"
+
+	// The language isn't an allowable language value
+	CodeLanguage = "language"
+
+	// A date field can't be parsed
+	CodeDate = "date"
+
+	// The docs field isn't empty or rssgo.DocsURL
+	CodeDocs = "docs"
+
+	// A numeric field is outside of its allowed range
+	CodeRange = "range"
+
+	// The cloud path doesn't start with a '/'
+	CodeCloudPath = "cloud-path"
+
+	// The cloud protocol isn't xml-rpc, soap, or http-post
+	CodeCloudProtocol = "cloud-protocol"
+
+	// A skip day isn't one of the days of the week
+	CodeSkipDay = "skip-day"
+
+	// An iTunes category isn't one of rssgo.ITunesCategories()
+	CodeITunesCategory = "itunes-category"
+
+	// An iTunes explicit value isn't true or false
+	CodeITunesExplicit = "itunes-explicit"
+
+	// An iTunes type or episode type isn't one of the allowed values
+	CodeITunesType = "itunes-type"
+
+	// An iTunes duration isn't seconds, MM:SS or HH:MM:SS
+	CodeITunesDuration = "itunes-duration"
+
+	// An iTunes image isn't a JPEG or PNG file
+	CodeITunesImage = "itunes-image"
+
+	// A Media RSS attribute isn't one of its allowed values
+	CodeMediaValue = "media-value"
+
+	// More than one media:content in a media:group is the default
+	CodeMediaDefault = "media-default"
+
+	// A Podcasting 2.0 value isn't one of its allowed values
+	CodePodcastValue = "podcast-value"
+
+	// A podcast:guid isn't a UUID
+	CodePodcastGuid = "podcast-guid"
+
+	// A GeoRSS or W3C geo value isn't valid coordinates
+	CodeGeo = "geo"
+
+	// A JSON Feed item id isn't unique within its feed. This is only
+	// reported by rssgo.VerifyJSONFeed
+	CodeDuplicateId = "duplicate-id"
+
+	// Best practice. An item doesn't have a guid
+	CodeMissingGuid = "missing-guid"
+
+	// Best practice. An item doesn't have a publication date
+	CodeMissingPubDate = "missing-pubdate"
+
+	// Best practice. An item has a description but no title
+	CodeMissingTitle = "missing-title"
+
+	// Best practice. The ttl is less than rssgo.MinTtl minutes
+	CodeSmallTtl = "small-ttl"
+
+	// Best practice. An item has content but no description for aggregators
+	// that only show descriptions
+	CodeMissingDescription = "missing-description"
+)
+
+// The smallest ttl, in minutes, that isn't reported by the best practice rules
+const MinTtl = 15
+
+// How serious a violation is
+type Severity int
+
+const (
+	// The feed doesn't conform to the spec
+	SeverityError Severity = iota
+
+	// The feed conforms to the spec but may cause problems
+	SeverityWarning
+
+	// The feed could be improved
+	SeverityInfo
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// A set of rules for rssgo.VerifyWithOptions
+type Profile int
+
+const (
+	// The rules of the RSS 2.0 spec, every violation is an error
+	ProfileStrict Profile = iota
+
+	// The rules of the RSS 2.0 spec plus the RSS Advisory Board's best
+	// practices (http://www.rssboard.org/rss-profile), which are reported as
+	// warnings
+	ProfileBestPractice
+
+	// Violations of the RSS 2.0 spec that aggregators commonly tolerate are
+	// reported as warnings, and best practices as info
+	ProfileLenient
+)
+
+// Options that control how a feed is verified
+type VerifyOptions struct {
+	// The rules to verify against, defaults to rssgo.ProfileStrict
+	Profile Profile
+
+	// The schemes that URLs may have, defaults to rssgo.DefaultURLSchemes()
+	URLSchemes []string
+}
+
+// The schemes that URLs may have if VerifyOptions.URLSchemes isn't set
+var defaultURLSchemes = []string{"http", "https"}
+
+// Returns the schemes that URLs may have if VerifyOptions.URLSchemes isn't
+// set.
+func DefaultURLSchemes() []string {
+	return append([]string(nil), defaultURLSchemes...)
+}
+
+// Rules that are only checked by the best practice and lenient profiles
+var bestPracticeCodes = map[string]bool{
+	CodeMissingGuid:        true,
+	CodeMissingPubDate:     true,
+	CodeMissingTitle:       true,
+	CodeSmallTtl:           true,
+	CodeMissingDescription: true,
+}
+
+// Rules that are only warnings in the lenient profile
+var tolerableCodes = map[string]bool{
+	CodeVersion:        true,
+	CodeLanguage:       true,
+	CodeDate:           true,
+	CodeDocs:           true,
+	CodeCloudProtocol:  true,
+	CodeSkipDay:        true,
+	CodeITunesExplicit: true,
+	CodeITunesDuration: true,
+}
+
+// A single violation of the RSS 2.0 spec
+type ValidationError struct {
+	// The path of the offending field, for example "Items[3].Enclosure.Length"
+	Field string
+
+	// The rule that was violated, one of the Code constants
+	Code string
+
+	// A description of the violation
+	Message string
+
+	// How serious the violation is
+	Severity Severity
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Field, e.Message)
+}
+
+// All of the violations found in a Rss object
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i := 0; i != len(e); i++ {
+		messages[i] = e[i].Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Returns the violations with rssgo.SeverityError
+func (e ValidationErrors) Errors() ValidationErrors {
+	return e.filter(SeverityError)
+}
+
+// Returns the violations with rssgo.SeverityWarning
+func (e ValidationErrors) Warnings() ValidationErrors {
+	return e.filter(SeverityWarning)
+}
+
+// Returns the violations with rssgo.SeverityInfo
+func (e ValidationErrors) Infos() ValidationErrors {
+	return e.filter(SeverityInfo)
+}
+
+func (e ValidationErrors) filter(severity Severity) ValidationErrors {
+	var filtered ValidationErrors
+	for i := 0; i != len(e); i++ {
+		if e[i].Severity == severity {
+			filtered = append(filtered, e[i])
+		}
+	}
+	return filtered
+}
+
+// Verifies that the contents of the Rss object will conform to the RSS 2.0
+// spec. Only the first violation is returned, see rssgo.VerifyAll and
+// rssgo.VerifyWithOptions.
+func Verify(r *Rss) error {
+	if errs := VerifyAll(r); len(errs) != 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// Verifies that the contents of the Rss object will conform to the RSS 2.0
+// spec. Every violation is returned, nil is returned if there are none.
+func VerifyAll(r *Rss) ValidationErrors {
+	return VerifyWithOptions(r, VerifyOptions{})
+}
+
+// Verifies the contents of the Rss object against the rules of a profile.
+// Every violation is returned with the severity the profile assigns it, nil is
+// returned if there are none.
+func VerifyWithOptions(r *Rss, opts VerifyOptions) ValidationErrors {
+	v := &verifier{profile: opts.Profile, schemes: opts.URLSchemes}
+
+	if r.Version != Version {
+		v.add("Version", CodeVersion, fmt.Sprintf("Bad version. Expecting %v", Version))
+	}
+
+	if r.Title == "" {
+		v.add("Title", CodeRequired, "Empty title. The title must be set")
+	}
+
+	v.verifyURL("Link", "channel link", r.Link)
+
+	if r.Description == "" {
+		v.add("Description", CodeRequired, "Empty description. The description must be set")
+	}
+
+	if r.Language != "" && !allowableLanguageMap[r.Language] {
+		v.add("Language", CodeLanguage, `Invalid language. Allowable language values are found
+at http://cyber.law.harvard.edu/rss/languages.html`)
+	}
+
+	if err := verifyDateField(r.PubDate); err != nil {
+		v.add("PubDate", CodeDate, fmt.Sprintf("Unable to parse the RSS PubDate (%v)", err))
+	}
+
+	if err := verifyDateField(r.LastBuildDate); err != nil {
+		v.add("LastBuildDate", CodeDate, fmt.Sprintf("Unable to parse the RSS LastBuildDate (%v)", err))
+	}
+
+	for i := 0; i != len(r.Categories); i++ {
+		if r.Categories[i].Category == "" {
+			v.add(fmt.Sprintf("Categories[%v].Category", i), CodeRequired, "Category should not be empty.")
+		}
+	}
+
+	if r.Docs != "" && r.Docs != DocsURL {
+		v.add("Docs", CodeDocs, fmt.Sprintf("Docs should be empty or %v", DocsURL))
+	}
+
+	if r.Cloud != nil {
+		if r.Cloud.Domain == "" {
+			v.add("Cloud.Domain", CodeRequired, "Cloud domain must not be empty")
+		}
+		if r.Cloud.Port < 1 || r.Cloud.Port > 65535 {
+			v.add("Cloud.Port", CodeRange, "Cloud port must be from 1 to 65535.")
+		}
+		if r.Cloud.Path == "" || r.Cloud.Path[0] != '/' {
+			v.add("Cloud.Path", CodeCloudPath, "Invalid cloud path.")
+		}
+		if r.Cloud.RegisterProcedure == "" {
+			v.add("Cloud.RegisterProcedure", CodeRequired, "Invalid cloud register procedure.")
+		}
+		if !allowableCloudProtocolMap[r.Cloud.Protocol] {
+			v.add("Cloud.Protocol", CodeCloudProtocol, "Invalid cloud protocol. It must be xml-rpc, soap, or http-post")
+		}
+	}
+
+	if r.Ttl < 0 {
+		v.add("Ttl", CodeRange, "Ttl field must be a positive integer.")
+	} else if r.Ttl > 0 && r.Ttl < MinTtl {
+		v.add("Ttl", CodeSmallTtl, fmt.Sprintf("Ttl should be at least %v minutes.", MinTtl))
+	}
+
+	if r.Image != nil {
+		v.verifyURL("Image.Url", "image url", r.Image.Url)
+
+		if r.Image.Title == "" {
+			v.add("Image.Title", CodeRequired, "Empty image title. The image title must be set")
+		}
+
+		v.verifyURL("Image.Link", "image link", r.Image.Link)
+
+		if r.Image.Width < 0 || r.Image.Width > 144 {
+			v.add("Image.Width", CodeRange, "Image width must be from 1 to 144.")
+		}
+
+		if r.Image.Height < 0 || r.Image.Height > 400 {
+			v.add("Image.Height", CodeRange, "Image heigth must be from 1 to 400.")
+		}
+	}
+
+	if r.TextInput != nil {
+		if r.TextInput.Title == "" {
+			v.add("TextInput.Title", CodeRequired, "Text input's title must be set.")
+		}
+
+		if r.TextInput.Description == "" {
+			v.add("TextInput.Description", CodeRequired, "Text input's description must be set.")
+		}
+
+		if r.TextInput.Name == "" {
+			v.add("TextInput.Name", CodeRequired, "Text input's name must be set.")
+		}
+
+		v.verifyURL("TextInput.Link", "text input's link", r.TextInput.Link)
+	}
+
+	if r.SkipHours != nil {
+		for h := 0; h != len(r.SkipHours.Hours); h++ {
+			hour := r.SkipHours.Hours[h]
+			if hour < 0 || hour > 23 {
+				v.add(fmt.Sprintf("SkipHours.Hours[%v]", h), CodeRange, "The skipHour's hour must be from 0 to 23")
+			}
+		}
+	}
+
+	if r.SkipDays != nil {
+		for d := 0; d != len(r.SkipDays.Days); d++ {
+			if !allowableSkipDays[r.SkipDays.Days[d]] {
+				v.add(fmt.Sprintf("SkipDays.Days[%v]", d), CodeSkipDay, "Invalid skip day. Allowable skip days can be found at http://cyber.law.harvard.edu/rss/skipHoursDays.html#skiphours")
+			}
+		}
+	}
+
+	v.verifyAtomLinks("AtomLinks", r.AtomLinks)
+
+	if r.ITunes != nil {
+		v.verifyITunesChannel("ITunes", r.ITunes)
+	}
+
+	if r.DC != nil {
+		v.verifyDublinCore("DC", r.DC)
+	}
+
+	if r.Podcast != nil {
+		v.verifyPodcastChannel("Podcast", r.Podcast)
+	}
+
+	if r.Geo != nil {
+		v.verifyGeo("Geo", r.Geo)
+	}
+
+	if r.Atom != nil {
+		v.verifyAtomChannel("Atom", r.Atom)
+	}
+
+	for i := 0; i != len(r.Items); i++ {
+		v.verifyItem(fmt.Sprintf("Items[%v]", i), &r.Items[i])
+	}
+
+	return v.errs
+}
+
+// Collects the violations found while verifying
+type verifier struct {
+	profile Profile
+	schemes []string
+	errs    ValidationErrors
+}
+
+// Records a violation with the severity that the profile assigns the rule.
+func (v *verifier) add(field, code, message string) {
+	severity := SeverityError
+	switch v.profile {
+	case ProfileStrict:
+		if bestPracticeCodes[code] {
+			return
+		}
+	case ProfileBestPractice:
+		if bestPracticeCodes[code] {
+			severity = SeverityWarning
+		}
+	case ProfileLenient:
+		if bestPracticeCodes[code] {
+			severity = SeverityInfo
+		} else if tolerableCodes[code] {
+			severity = SeverityWarning
+		}
+	}
+	v.errs = append(v.errs, ValidationError{field, code, message, severity})
+}
+
+// Verifies that a URL is absolute, has one of the allowed schemes and has a
+// host. Internationalized domain names and IRIs are allowed.
+func (v *verifier) verifyURL(field, name, value string) {
+	if value == "" {
+		v.add(field, CodeRequired, fmt.Sprintf("Empty %v. The %v must be set", name, name))
+		return
+	}
+
+	u, err := url.Parse(value)
+	if err != nil {
+		v.add(field, CodeURL, fmt.Sprintf("Bad %v. Expecting a valid URL (%v)", name, err))
+		return
+	}
+
+	if !u.IsAbs() {
+		v.add(field, CodeURLNotAbsolute, fmt.Sprintf("Bad %v. Expecting an absolute URL", name))
+		return
+	}
+
+	schemes := v.schemes
+	if schemes == nil {
+		schemes = defaultURLSchemes
+	}
+	allowed := false
+	for _, scheme := range schemes {
+		if strings.EqualFold(u.Scheme, scheme) {
+			allowed = true
+		}
+	}
+	if !allowed {
+		v.add(field, CodeURLScheme, fmt.Sprintf("Bad %v. Expecting a URL with a scheme of %v",
+			name, strings.Join(schemes, ", ")))
+		return
+	}
+
+	if u.Hostname() == "" {
+		v.add(field, CodeURLHost, fmt.Sprintf("Bad %v. Expecting a URL with a host", name))
+	}
+}
+
+// Verifies that the item found at path conforms to the RSS 2.0 spec.
+func (v *verifier) verifyItem(path string, item *Item) {
+	if item.Title == "" {
+		if item.Description == "" {
+			v.add(path+".Title", CodeRequired, "The item title or description must be set.")
+		} else {
+			v.add(path+".Title", CodeMissingTitle, "The item should have a title.")
+		}
+	}
+
+	if item.Content != "" && strings.TrimSpace(item.Content) == "" {
+		v.add(path+".Content", CodeRequired, "The item content:encoded must not be only white space.")
+	} else if item.Content != "" && item.Description == "" {
+		v.add(path+".Description", CodeMissingDescription,
+			"The item should have a description as well as content.")
+	}
+
+	if item.Link != "" {
+		v.verifyURL(path+".Link", "item link", item.Link)
+	}
+
+	if item.Comments != "" {
+		v.verifyURL(path+".Comments", "item comments", item.Comments)
+	}
+
+	v.verifyComments(path, item)
+
+	if item.Enclosure != nil {
+		v.verifyURL(path+".Enclosure.Url", "item enclosure url", item.Enclosure.Url)
+
+		if item.Enclosure.Length <= 0 {
+			v.add(path+".Enclosure.Length", CodeRange, "The item enclosure length should not be greater than zero.")
+		}
+
+		if item.Enclosure.Type == "" {
+			v.add(path+".Enclosure.Type", CodeRequired, "The item enclosure type must be set.")
+		}
+	}
+
+	if item.Guid == nil {
+		v.add(path+".Guid", CodeMissingGuid, "The item should have a guid.")
+	} else {
+		if item.Guid.IsPermaLink {
+			v.verifyURL(path+".Guid.Guid", "item guid body", item.Guid.Guid)
+		}
+	}
+
+	if item.PubDate.IsZero() {
+		v.add(path+".PubDate", CodeMissingPubDate, "The item should have a publication date.")
+	} else if err := verifyDateField(item.PubDate); err != nil {
+		v.add(path+".PubDate", CodeDate, fmt.Sprintf("Unable to parse the item PubDate (%v)", err))
+	}
+
+	if item.Source != nil {
+		if item.Source.Source == "" {
+			v.add(path+".Source.Source", CodeRequired, "The item source must be set.")
+		}
+
+		v.verifyURL(path+".Source.Url", "item source url", item.Source.Url)
+	}
+
+	if item.ITunes != nil {
+		v.verifyITunesItem(path+".ITunes", item.ITunes)
+	}
+
+	if item.DC != nil {
+		v.verifyDublinCore(path+".DC", item.DC)
+	}
+
+	if item.Media != nil {
+		v.verifyMediaItem(path+".Media", item.Media)
+	}
+
+	if item.Podcast != nil {
+		v.verifyPodcastItem(path+".Podcast", item.Podcast)
+	}
+
+	if item.Geo != nil {
+		v.verifyGeo(path+".Geo", item.Geo)
+	}
+
+	if item.Atom != nil {
+		v.verifyAtomItem(path+".Atom", item.Atom)
+	}
+}
+
+// Verifies a single item, the item's index is used in the field paths.
+func verifyItem(index int, item *Item) ValidationErrors {
+	v := &verifier{}
+	v.verifyItem(fmt.Sprintf("Items[%v]", index), item)
+	return v.errs
+}
+
+// Verifies that a date field's text, if it's written, is an RFC822 date. A
+// changed Time is written with rssgo.ComposeDate so it's always valid. A date
+// that RssTime couldn't parse, such as one with an unknown time zone name
+// that ParseRssDate treats as UTC, is also reported.
+func verifyDateField(field RssTime) error {
+	if field.Text != "" && !field.changed() {
+		if _, err := ParseRssDate(field.Text); err != nil {
+			return err
+		}
+		if field.Time.IsZero() {
+			if _, _, err := ParseDate(field.Text, DateLenient); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
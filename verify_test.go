@@ -0,0 +1,184 @@
+// Copyright 2012 Evan Farrer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rssgo
+
+import (
+	"testing"
+)
+
+func TestVerifyAll(t *testing.T) {
+
+	rss := &Rss{Version: Version,
+		Title:       "title",
+		Link:        "http://github.com/efarrer/rssgo/",
+		Description: "A podcast"}
+	if errs := VerifyAll(rss); errs != nil {
+		t.Fatalf("VerifyAll should pass: %v\n", errs)
+	}
+
+	rss.Version = "0.0"
+	rss.Title = ""
+	rss.Ttl = -1
+	rss.SkipHours = &Hours{[]int{1, 24}}
+	rss.Items = []Item{
+		{Title: "title"},
+		{Title: "title"},
+		{Title: "title"},
+		{Enclosure: &Enclosure{Url: "http://enclosure/music.mp3", Length: 0},
+			PubDate: RssTimeFromString("Some time tomorrow")}}
+
+	expected := ValidationErrors{
+		{Field: "Version", Code: CodeVersion},
+		{Field: "Title", Code: CodeRequired},
+		{Field: "Ttl", Code: CodeRange},
+		{Field: "SkipHours.Hours[1]", Code: CodeRange},
+		{Field: "Items[3].Title", Code: CodeRequired},
+		{Field: "Items[3].Enclosure.Length", Code: CodeRange},
+		{Field: "Items[3].Enclosure.Type", Code: CodeRequired},
+		{Field: "Items[3].PubDate", Code: CodeDate},
+	}
+
+	errs := VerifyAll(rss)
+	if len(errs) != len(expected) {
+		t.Fatalf("VerifyAll returned %v errors expected %v: %v\n", len(errs), len(expected), errs)
+	}
+	for i := 0; i != len(expected); i++ {
+		if errs[i].Field != expected[i].Field || errs[i].Code != expected[i].Code {
+			t.Fatalf("VerifyAll returned unexpected error expected: %v/%v got: %v/%v\n",
+				expected[i].Field, expected[i].Code, errs[i].Field, errs[i].Code)
+		}
+		if errs[i].Message == "" {
+			t.Fatalf("VerifyAll returned an error without a message %#v\n", errs[i])
+		}
+	}
+
+	err := Verify(rss)
+	if verr, ok := err.(ValidationError); !ok || verr != errs[0] {
+		t.Fatalf("Verify should return the first error expected: %v got: %v\n", errs[0], err)
+	}
+}
+
+func TestVerifyWithOptions(t *testing.T) {
+
+	rss := &Rss{Version: Version,
+		Title:       "title",
+		Link:        "http://github.com/efarrer/rssgo/",
+		Description: "A podcast",
+		Language:    "pig-latin",
+		Ttl:         5,
+		Items: []Item{
+			{Title: "title", Guid: &Guid{Guid: "guid"}, PubDate: RssTimeFromString("23 Jul 74 09:10 UTC")},
+			{Description: "description"},
+			{}}}
+
+	severities := func(errs ValidationErrors) map[string]Severity {
+		found := map[string]Severity{}
+		for _, err := range errs {
+			found[err.Field+"/"+err.Code] = err.Severity
+		}
+		return found
+	}
+	checkSeverities := func(profile Profile, expected map[string]Severity) {
+		actual := severities(VerifyWithOptions(rss, VerifyOptions{Profile: profile}))
+		if len(actual) != len(expected) {
+			t.Fatalf("Profile %v returned unexpected violations expected: %v got: %v\n",
+				profile, expected, actual)
+		}
+		for key, severity := range expected {
+			if s, ok := actual[key]; !ok || s != severity {
+				t.Fatalf("Profile %v returned unexpected severity for %v expected: %v got: %v\n",
+					profile, key, severity, s)
+			}
+		}
+	}
+
+	checkSeverities(ProfileStrict, map[string]Severity{
+		"Language/language":       SeverityError,
+		"Items[2].Title/required": SeverityError,
+	})
+
+	checkSeverities(ProfileBestPractice, map[string]Severity{
+		"Language/language":                SeverityError,
+		"Ttl/small-ttl":                    SeverityWarning,
+		"Items[1].Title/missing-title":     SeverityWarning,
+		"Items[1].Guid/missing-guid":       SeverityWarning,
+		"Items[1].PubDate/missing-pubdate": SeverityWarning,
+		"Items[2].Title/required":          SeverityError,
+		"Items[2].Guid/missing-guid":       SeverityWarning,
+		"Items[2].PubDate/missing-pubdate": SeverityWarning,
+	})
+
+	checkSeverities(ProfileLenient, map[string]Severity{
+		"Language/language":                SeverityWarning,
+		"Ttl/small-ttl":                    SeverityInfo,
+		"Items[1].Title/missing-title":     SeverityInfo,
+		"Items[1].Guid/missing-guid":       SeverityInfo,
+		"Items[1].PubDate/missing-pubdate": SeverityInfo,
+		"Items[2].Title/required":          SeverityError,
+		"Items[2].Guid/missing-guid":       SeverityInfo,
+		"Items[2].PubDate/missing-pubdate": SeverityInfo,
+	})
+
+	errs := VerifyWithOptions(rss, VerifyOptions{Profile: ProfileLenient})
+	if len(errs.Errors()) != 1 || len(errs.Warnings()) != 1 || len(errs.Infos()) != 6 {
+		t.Fatalf("Unexpected number of errors, warnings and info %v\n", errs)
+	}
+
+	rss.Language = ""
+	rss.Items = rss.Items[:1]
+	if errs := VerifyWithOptions(rss, VerifyOptions{Profile: ProfileLenient}); len(errs.Errors()) != 0 {
+		t.Fatalf("Lenient profile should not report errors %v\n", errs)
+	}
+	if err := Verify(rss); err != nil {
+		t.Fatalf("Best practice violations should not fail Verify %v\n", err)
+	}
+}
+
+func TestVerifyURL(t *testing.T) {
+
+	verifyLink := func(link string, schemes []string) ValidationErrors {
+		rss := &Rss{Version: Version,
+			Title:       "title",
+			Link:        "http://github.com/efarrer/rssgo/",
+			Description: "A podcast",
+			Items:       []Item{{Title: "title", Link: link}}}
+		return VerifyWithOptions(rss, VerifyOptions{URLSchemes: schemes})
+	}
+	linkShouldPass := func(link string, schemes []string) {
+		if errs := verifyLink(link, schemes); errs != nil {
+			t.Fatalf("Link %v should pass: %v\n", link, errs)
+		}
+	}
+	linkShouldFail := func(link string, schemes []string, code string) {
+		errs := verifyLink(link, schemes)
+		if len(errs) != 1 || errs[0].Field != "Items[0].Link" || errs[0].Code != code {
+			t.Fatalf("Link %v should fail with %v: %v\n", link, code, errs)
+		}
+	}
+
+	linkShouldPass("http://example.com", nil)
+	linkShouldPass("HTTPS://example.com/path?query#fragment", nil)
+	linkShouldPass("http://bücher.example/straße", nil)
+	linkShouldPass("http://xn--bcher-kva.example/", nil)
+	linkShouldPass("http://例え.テスト/パス", nil)
+	linkShouldPass("ftp://example.com/file", []string{"http", "ftp"})
+
+	linkShouldFail("/relative/path", nil, CodeURLNotAbsolute)
+	linkShouldFail("#fragment", nil, CodeURLNotAbsolute)
+	linkShouldFail("ftp://example.com/file", nil, CodeURLScheme)
+	linkShouldFail("https://example.com/", []string{"http"}, CodeURLScheme)
+	linkShouldFail("mailto:someone@example.com", nil, CodeURLScheme)
+	linkShouldFail("http:///path", nil, CodeURLHost)
+	linkShouldFail("http:opaque", nil, CodeURLHost)
+	linkShouldFail("http://exa mple.com/", nil, CodeURL)
+
+	// Changing the returned schemes doesn't change what's allowed
+	schemes := DefaultURLSchemes()
+	schemes[0] = "ftp"
+	linkShouldFail("ftp://example.com/file", nil, CodeURLScheme)
+	if DefaultURLSchemes()[0] != "http" {
+		t.Fatalf("Expected DefaultURLSchemes to return a copy\n")
+	}
+}